@@ -5,6 +5,7 @@ from app.core.exception import AppError
 from app.core.instrumentation import instrument_app
 from app.core.logging import RequestIdMiddleware, logger
 from app.router.embedding import router as embedding_router
+from app.router.rerank import router as rerank_router
 from app.router.root import router as root_router
 from fastapi import FastAPI, Request
 from fastapi.middleware.cors import CORSMiddleware
@@ -53,6 +54,7 @@ app.add_middleware(RequestIdMiddleware)
 
 app.include_router(root_router)
 app.include_router(embedding_router)
+app.include_router(rerank_router)
 
 
 @app.exception_handler(AppError)
@@ -0,0 +1,30 @@
+from typing import List
+from app.core.instrumentation import get_tracer
+from app.core.response import ErrorResponse, SuccessResponse
+from app.services.dependency import DepRerankService
+from app.schemas.rerank import RerankInput
+from fastapi import APIRouter
+
+router = APIRouter(
+    prefix="/rerank",
+    tags=["rerank"],
+)
+
+tracer = get_tracer("api.rerank")
+
+
+@router.post(
+    "",
+    responses={
+        200: {"model": SuccessResponse[List[float]]},
+        400: {"model": ErrorResponse},
+        502: {"model": ErrorResponse},
+        503: {"model": ErrorResponse},
+    },
+)
+def rerank(service: DepRerankService, input: RerankInput):
+    with tracer.start_as_current_span("route.rerank") as span:
+        span.set_attribute("http.method", "POST")
+        span.set_attribute("http.route", "/rerank")
+        scores = service.rerank(input)
+        return scores
@@ -1,6 +1,8 @@
+from functools import lru_cache
 from typing import Annotated
 
 from app.services.embedding import EmbeddingService
+from app.services.rerank import RerankService
 from fastapi import Depends
 
 
@@ -10,3 +12,19 @@ def get_embedding_service() -> EmbeddingService:
 
 
 DepEmbeddingService = Annotated[EmbeddingService, Depends(get_embedding_service)]
+
+
+@lru_cache
+def get_rerank_service() -> RerankService:
+    """Get the cached rerank service.
+
+    Uses lru_cache decorator so that the cross-encoder is loaded once, not per request.
+
+    Returns:
+        RerankService: Rerank service instance.
+    """
+    rerank_service = RerankService()
+    return rerank_service
+
+
+DepRerankService = Annotated[RerankService, Depends(get_rerank_service)]
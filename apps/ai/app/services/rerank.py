@@ -0,0 +1,27 @@
+from typing import List
+from app.core.instrumentation import get_tracer
+from app.core.logging import get_logger
+from app.core.response import ErrorResponse, SuccessResponse, success_response
+from sentence_transformers import CrossEncoder
+
+from app.schemas.rerank import RerankInput
+
+tracer = get_tracer("service.rerank")
+
+
+class RerankService:
+    __log = get_logger()
+
+    def __init__(self):
+        self.cross_encoder = CrossEncoder("ncbi/MedCPT-Cross-Encoder")
+
+    def rerank(self, input: RerankInput) -> SuccessResponse[List[float]] | ErrorResponse:
+        with tracer.start_as_current_span("service.rerank") as span:
+            self.__log.info("Service layer log", extra={"layer": "service"})
+            span.set_attribute("rerank.documents", len(input.documents))
+            if not input.documents:
+                return success_response([])
+            scores = self.cross_encoder.predict(
+                [(input.query, document) for document in input.documents]
+            )
+            return success_response(scores.tolist())
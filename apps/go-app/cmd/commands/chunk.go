@@ -0,0 +1,50 @@
+package commands
+
+import (
+	"context"
+	"flag"
+	"go-app/database"
+	"go-app/internal/logging"
+	httpRepo "go-app/internal/repository/http"
+	"go-app/internal/repository/postgres"
+	"go-app/service"
+	"log/slog"
+)
+
+// runChunking splits the content of every journal without chunks into
+// passages and stores them with their embeddings, batch journals at a time.
+// It is safe to rerun: journals that already have chunks are skipped.
+func runChunking(args []string) error {
+	flags := flag.NewFlagSet("chunk", flag.ContinueOnError)
+	batch := flags.Int("batch", 50, "number of journals chunked per batch")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	pool, err := database.SetupPgxPool()
+	if err != nil {
+		return err
+	}
+	defer pool.Close()
+
+	chunkService := service.NewJournalChunkService(
+		postgres.NewJournalChunkRepository(pool),
+		httpRepo.NewEmbeddingHTTPRepository(),
+	)
+
+	total := 0
+	for {
+		n, err := chunkService.ChunkPendingJournals(ctx, *batch)
+		if err != nil {
+			return err
+		}
+		if n == 0 {
+			break
+		}
+		total += n
+		logging.LogInfo(ctx, "Chunked journals", slog.Int("batch", n), slog.Int("total", total))
+	}
+
+	return nil
+}
@@ -0,0 +1,112 @@
+package commands
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"flag"
+	"fmt"
+	"go-app/database"
+	"go-app/domain"
+	"go-app/internal/logging"
+	"go-app/internal/repository/postgres"
+	"go-app/service"
+	"io"
+	"log/slog"
+	"os"
+	"strconv"
+)
+
+// runCitationImport stores the citations of a CSV file of citing_pmid,
+// cited_pmid rows, with an optional header, batch rows at a time. It is safe
+// to rerun: citations already stored are skipped.
+func runCitationImport(args []string) error {
+	flags := flag.NewFlagSet("citations import", flag.ContinueOnError)
+	batch := flags.Int("batch", 10000, "number of citations stored per batch")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 || *batch < 1 {
+		return errors.New("usage: citations import [-batch n] <file.csv>, with n at least 1")
+	}
+
+	file, err := os.Open(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	ctx := context.Background()
+	pool, err := database.SetupPgxPool()
+	if err != nil {
+		return err
+	}
+	defer pool.Close()
+
+	citationService := service.NewCitationService(postgres.NewJournalCitationRepository(pool))
+
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = 2
+	reader.ReuseRecord = true
+	citations := make([]domain.JournalCitation, 0, *batch)
+	var read, stored int64
+	flush := func() error {
+		n, err := citationService.ImportCitations(ctx, citations)
+		if err != nil {
+			return err
+		}
+		stored += n
+		citations = citations[:0]
+		logging.LogInfo(ctx, "Imported citations", slog.Int64("read", read), slog.Int64("stored", stored))
+		return nil
+	}
+	for line := 1; ; line++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		citing, citingErr := strconv.ParseInt(record[0], 10, 64)
+		cited, citedErr := strconv.ParseInt(record[1], 10, 64)
+		if citingErr != nil || citedErr != nil {
+			if line == 1 {
+				continue // header
+			}
+			return fmt.Errorf("line %d: PMIDs must be integers", line)
+		}
+
+		read++
+		citations = append(citations, domain.JournalCitation{CitingPMID: citing, CitedPMID: cited})
+		if len(citations) == *batch {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+	}
+
+	return flush()
+}
+
+// runAuthorityComputation computes the citation count and the PageRank
+// authority of every journal from the stored citations, for ranking profiles
+// to boost by. It is meant to run offline, after citations were imported.
+func runAuthorityComputation() error {
+	ctx := context.Background()
+	pool, err := database.SetupPgxPool()
+	if err != nil {
+		return err
+	}
+	defer pool.Close()
+
+	citationService := service.NewCitationService(postgres.NewJournalCitationRepository(pool))
+	n, err := citationService.ComputeCitationSignals(ctx)
+	if err != nil {
+		return err
+	}
+	logging.LogInfo(ctx, "Computed journal citation signals", slog.Int("journals", n))
+
+	return nil
+}
@@ -0,0 +1,17 @@
+package commands
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunCitationImport(t *testing.T) {
+	t.Run("Rejects a batch size below 1", func(t *testing.T) {
+		for _, batch := range []string{"0", "-1"} {
+			err := runCitationImport([]string{"-batch", batch, "citations.csv"})
+
+			assert.ErrorContains(t, err, "usage: citations import", batch)
+		}
+	})
+}
@@ -0,0 +1,301 @@
+package commands
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"go-app/database"
+	"go-app/domain"
+	httpRepo "go-app/internal/repository/http"
+	"go-app/internal/repository/postgres"
+	"go-app/service"
+	"math"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+)
+
+const (
+	evalNDCGDepth   = 10
+	evalRecallDepth = 100
+)
+
+// evalQuery is one topic of the query file with its relevance judgements,
+// keyed by PMID.
+type evalQuery struct {
+	ID        string
+	Text      string
+	Judgments map[int64]int
+}
+
+type evalResult struct {
+	Mode    string  `json:"mode"`
+	Queries int     `json:"queries"`
+	NDCG    float64 `json:"ndcg@10"`
+	MRR     float64 `json:"mrr"`
+	Recall  float64 `json:"recall@100"`
+	Skipped int     `json:"skipped"` // queries without any relevant judgement
+}
+
+// evalModes maps each evaluated search mode to the filter it runs a query as.
+var evalModes = map[string]func(text string) *domain.JournalFilter{
+	"lexical": func(text string) *domain.JournalFilter {
+		return &domain.JournalFilter{Search: domain.EscapeSearchQuery(text)}
+	},
+	"generalist": func(text string) *domain.JournalFilter {
+		return &domain.JournalFilter{VSearch: text, Type: domain.GeneralVectorType}
+	},
+	"specialist": func(text string) *domain.JournalFilter {
+		return &domain.JournalFilter{VSearch: text, Type: domain.SpecialistVectorType}
+	},
+	"ensemble": func(text string) *domain.JournalFilter {
+		return &domain.JournalFilter{VSearch: text, Type: domain.EnsembleVectorType}
+	},
+	"hybrid": func(text string) *domain.JournalFilter {
+		return &domain.JournalFilter{
+			Search:  domain.EscapeSearchQuery(text),
+			VSearch: text,
+			Type:    domain.SpecialistVectorType,
+			Fusion:  domain.RRFFusion,
+		}
+	},
+}
+
+// runRelevanceEvaluation runs every query of a query file through
+// JournalService.GetJournalList in each search mode and scores the rankings
+// against TREC-style qrels with nDCG@10, MRR and recall@100.
+//
+// The query file has one "<query id>\t<query text>" line per topic and the
+// qrels file the usual "<query id> <iteration> <pmid> <relevance>" lines.
+func runRelevanceEvaluation(args []string) error {
+	flags := flag.NewFlagSet("eval", flag.ContinueOnError)
+	qrelsPath := flags.String("qrels", "", "path of the TREC-style qrels file")
+	queriesPath := flags.String("queries", "", "path of the tab separated query file")
+	modes := flags.String("modes", "lexical,generalist,specialist,ensemble,hybrid", "comma separated search modes")
+	format := flags.String("format", "table", "output format: table or json")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *qrelsPath == "" || *queriesPath == "" {
+		return fmt.Errorf("both -qrels and -queries are required")
+	}
+	if *format != "table" && *format != "json" {
+		return fmt.Errorf("unknown output format %q", *format)
+	}
+
+	queries, err := readEvalQueries(*queriesPath, *qrelsPath)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	pool, err := database.SetupPgxPool()
+	if err != nil {
+		return err
+	}
+	defer pool.Close()
+
+	journalService := service.NewJournalService(
+		postgres.NewJournalRepository(pool),
+		httpRepo.NewEmbeddingHTTPRepository(),
+	)
+
+	var results []evalResult
+	for _, mode := range strings.Split(*modes, ",") {
+		newFilter, ok := evalModes[strings.TrimSpace(mode)]
+		if !ok {
+			return fmt.Errorf("unknown search mode %q", mode)
+		}
+		result, err := evaluateMode(ctx, journalService, strings.TrimSpace(mode), newFilter, queries)
+		if err != nil {
+			return fmt.Errorf("%s: %w", mode, err)
+		}
+		results = append(results, result)
+	}
+
+	if *format == "json" {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(results)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "mode\tqueries\tnDCG@10\tMRR\trecall@100")
+	for _, r := range results {
+		fmt.Fprintf(w, "%s\t%d\t%.4f\t%.4f\t%.4f\n", r.Mode, r.Queries, r.NDCG, r.MRR, r.Recall)
+	}
+	return w.Flush()
+}
+
+func evaluateMode(
+	ctx context.Context,
+	journalService *service.JournalService,
+	mode string,
+	newFilter func(text string) *domain.JournalFilter,
+	queries []evalQuery,
+) (evalResult, error) {
+	result := evalResult{Mode: mode}
+	limit, page := evalRecallDepth, 0
+
+	for _, q := range queries {
+		if relevantCount(q.Judgments) == 0 {
+			result.Skipped++
+			continue
+		}
+
+		filter := newFilter(q.Text)
+		filter.Limit, filter.Page = &limit, &page
+		list, err := journalService.GetJournalList(ctx, filter)
+		if err != nil {
+			return result, fmt.Errorf("query %s: %w", q.ID, err)
+		}
+
+		ranking := make([]int64, len(list.Journals))
+		for i, j := range list.Journals {
+			ranking[i] = j.PMID
+		}
+
+		result.NDCG += ndcgAt(ranking, q.Judgments, evalNDCGDepth)
+		result.MRR += reciprocalRank(ranking, q.Judgments)
+		result.Recall += recallAt(ranking, q.Judgments, evalRecallDepth)
+		result.Queries++
+	}
+
+	if result.Queries > 0 {
+		n := float64(result.Queries)
+		result.NDCG /= n
+		result.MRR /= n
+		result.Recall /= n
+	}
+	return result, nil
+}
+
+func readEvalQueries(queriesPath, qrelsPath string) ([]evalQuery, error) {
+	judgments := map[string]map[int64]int{}
+	err := scanLines(qrelsPath, func(line string) error {
+		fields := strings.Fields(line)
+		if len(fields) != 4 {
+			return fmt.Errorf("expected 4 fields, got %d", len(fields))
+		}
+		pmid, err := strconv.ParseInt(fields[2], 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid pmid %q", fields[2])
+		}
+		rel, err := strconv.Atoi(fields[3])
+		if err != nil {
+			return fmt.Errorf("invalid relevance %q", fields[3])
+		}
+		if judgments[fields[0]] == nil {
+			judgments[fields[0]] = map[int64]int{}
+		}
+		judgments[fields[0]][pmid] = rel
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var queries []evalQuery
+	err = scanLines(queriesPath, func(line string) error {
+		id, text, ok := strings.Cut(line, "\t")
+		if !ok || strings.TrimSpace(text) == "" {
+			return fmt.Errorf("expected \"<query id>\\t<query text>\"")
+		}
+		queries = append(queries, evalQuery{ID: id, Text: strings.TrimSpace(text), Judgments: judgments[id]})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return queries, nil
+}
+
+// scanLines calls fn with every non-blank line of the file at path.
+func scanLines(path string, fn func(line string) error) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		if err := fn(line); err != nil {
+			return fmt.Errorf("%s:%d: %w", path, n, err)
+		}
+	}
+	return scanner.Err()
+}
+
+func relevantCount(judgments map[int64]int) int {
+	n := 0
+	for _, rel := range judgments {
+		if rel > 0 {
+			n++
+		}
+	}
+	return n
+}
+
+// ndcgAt returns the normalized discounted cumulative gain of the first k
+// results, using the graded relevance as gain.
+func ndcgAt(ranking []int64, judgments map[int64]int, k int) float64 {
+	dcg := 0.0
+	for i, pmid := range ranking[:min(k, len(ranking))] {
+		if rel := judgments[pmid]; rel > 0 {
+			dcg += float64(rel) / math.Log2(float64(i+2))
+		}
+	}
+
+	var ideal []int
+	for _, rel := range judgments {
+		if rel > 0 {
+			ideal = append(ideal, rel)
+		}
+	}
+	slices.SortFunc(ideal, func(a, b int) int { return b - a })
+	idcg := 0.0
+	for i, rel := range ideal[:min(k, len(ideal))] {
+		idcg += float64(rel) / math.Log2(float64(i+2))
+	}
+
+	if idcg == 0 {
+		return 0
+	}
+	return dcg / idcg
+}
+
+// reciprocalRank returns 1/rank of the first relevant result, or 0.
+func reciprocalRank(ranking []int64, judgments map[int64]int) float64 {
+	for i, pmid := range ranking {
+		if judgments[pmid] > 0 {
+			return 1 / float64(i+1)
+		}
+	}
+	return 0
+}
+
+// recallAt returns the share of relevant journals found in the first k results.
+func recallAt(ranking []int64, judgments map[int64]int, k int) float64 {
+	relevant := relevantCount(judgments)
+	if relevant == 0 {
+		return 0
+	}
+
+	found := 0
+	for _, pmid := range ranking[:min(k, len(ranking))] {
+		if judgments[pmid] > 0 {
+			found++
+		}
+	}
+	return float64(found) / float64(relevant)
+}
@@ -0,0 +1,89 @@
+package commands
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEvalMetrics(t *testing.T) {
+	// 20 and 30 are relevant with grades 2 and 1, and 50, the most relevant,
+	// is never retrieved.
+	graded := map[int64]int{10: 0, 20: 2, 30: 1, 50: 3}
+
+	cases := []struct {
+		name      string
+		ranking   []int64
+		judgments map[int64]int
+		k         int
+		ndcg      float64
+		rr        float64
+		recall    float64
+	}{
+		{
+			// DCG = 2/log2(3) + 1/log2(4), IDCG = 3 + 2/log2(3) + 1/log2(4).
+			name:      "Graded judgments",
+			ranking:   []int64{10, 20, 30, 40},
+			judgments: graded,
+			k:         3,
+			ndcg:      0.36999401273810767,
+			rr:        0.5,
+			recall:    2.0 / 3,
+		},
+		{
+			name:      "Cutoff before the first relevant result",
+			ranking:   []int64{10, 20, 30, 40},
+			judgments: graded,
+			k:         1,
+			ndcg:      0,
+			rr:        0.5,
+			recall:    0,
+		},
+		{
+			name:      "Ideal ranking",
+			ranking:   []int64{20, 30},
+			judgments: map[int64]int{20: 2, 30: 1},
+			k:         2,
+			ndcg:      1,
+			rr:        1,
+			recall:    1,
+		},
+		{
+			// DCG = 2, IDCG = 2 + 1/log2(3): the ideal ranking is not cut
+			// to the shorter result list.
+			name:      "k larger than the result count",
+			ranking:   []int64{20},
+			judgments: map[int64]int{20: 2, 30: 1},
+			k:         10,
+			ndcg:      0.7601875334318685,
+			rr:        1,
+			recall:    0.5,
+		},
+		{
+			name:      "No relevant journals",
+			ranking:   []int64{10, 20},
+			judgments: map[int64]int{10: 0},
+			k:         10,
+			ndcg:      0,
+			rr:        0,
+			recall:    0,
+		},
+		{
+			name:      "No results",
+			ranking:   nil,
+			judgments: graded,
+			k:         10,
+			ndcg:      0,
+			rr:        0,
+			recall:    0,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.InDelta(t, tc.ndcg, ndcgAt(tc.ranking, tc.judgments, tc.k), 1e-12)
+			assert.InDelta(t, tc.rr, reciprocalRank(tc.ranking, tc.judgments), 1e-12)
+			assert.InDelta(t, tc.recall, recallAt(tc.ranking, tc.judgments, tc.k), 1e-12)
+		})
+	}
+}
@@ -0,0 +1,151 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"go-app/database"
+	"go-app/domain"
+	"go-app/internal/repository/postgres"
+	"os"
+	"slices"
+	"text/tabwriter"
+	"time"
+)
+
+type recallResult struct {
+	vType        domain.VectorType
+	queries      int
+	recall       float64
+	annLatency   []time.Duration
+	exactLatency []time.Duration
+}
+
+// runRecallEvaluation samples stored journal embeddings as queries and runs
+// each of them through both the HNSW index and an exact brute-force search,
+// reporting recall@k of the index and the latency of both per vector type.
+func runRecallEvaluation(args []string) error {
+	flags := flag.NewFlagSet("recall", flag.ContinueOnError)
+	k := flags.Int("k", 10, "number of neighbours compared per query")
+	samples := flags.Int("samples", 100, "number of sampled queries per vector type")
+	profile := flags.String("profile", string(domain.BalancedRecall), "recall profile of the ANN search")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *k < 1 || *samples < 1 {
+		return errors.New("usage: recall [-k n] [-samples n] [-profile name], with n at least 1")
+	}
+
+	check := domain.JournalFilter{VSearch: "-", Type: domain.GeneralVectorType, Recall: domain.RecallProfile(*profile)}
+	if err := check.Validate(); err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	pool, err := database.SetupPgxPool()
+	if err != nil {
+		return err
+	}
+	defer pool.Close()
+
+	repo := postgres.NewJournalRepository(pool)
+	var results []recallResult
+	for _, vType := range []domain.VectorType{domain.GeneralVectorType, domain.SpecialistVectorType} {
+		result, err := evaluateRecall(ctx, repo, vType, *k, *samples, domain.RecallProfile(*profile))
+		if err != nil {
+			return fmt.Errorf("%s: %w", vType, err)
+		}
+		results = append(results, result)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "type\tqueries\trecall@%d\tann p50\tann p95\texact p50\texact p95\n", *k)
+	for _, r := range results {
+		fmt.Fprintf(w, "%s\t%d\t%.4f\t%s\t%s\t%s\t%s\n",
+			r.vType, r.queries, r.recall,
+			percentile(r.annLatency, 0.50), percentile(r.annLatency, 0.95),
+			percentile(r.exactLatency, 0.50), percentile(r.exactLatency, 0.95),
+		)
+	}
+	return w.Flush()
+}
+
+func evaluateRecall(
+	ctx context.Context,
+	repo *postgres.JournalRepository,
+	vType domain.VectorType,
+	k int,
+	samples int,
+	profile domain.RecallProfile,
+) (recallResult, error) {
+	result := recallResult{vType: vType}
+
+	queries, err := repo.SampleEmbeddings(ctx, vType, samples)
+	if err != nil {
+		return result, err
+	}
+
+	page := 0
+	var recallSum float64
+	for _, query := range queries {
+		embeddings := domain.QueryEmbeddings{vType: query}
+		// VSearch only has to be set for the repository to search by the
+		// sampled vector; its text is never embedded.
+		filter := &domain.JournalFilter{
+			Limit:   &k,
+			Page:    &page,
+			VSearch: "recall evaluation",
+			Type:    vType,
+			Recall:  profile,
+		}
+
+		start := time.Now()
+		ann, err := repo.GetJournalList(ctx, filter, embeddings)
+		if err != nil {
+			return result, err
+		}
+		result.annLatency = append(result.annLatency, time.Since(start))
+
+		filter.Exact = true
+		start = time.Now()
+		exact, err := repo.GetJournalList(ctx, filter, embeddings)
+		if err != nil {
+			return result, err
+		}
+		result.exactLatency = append(result.exactLatency, time.Since(start))
+
+		if len(exact) == 0 {
+			continue
+		}
+		truth := make(map[int64]struct{}, len(exact))
+		for _, j := range exact {
+			truth[j.PMID] = struct{}{}
+		}
+		hits := 0
+		for _, j := range ann {
+			if _, ok := truth[j.PMID]; ok {
+				hits++
+			}
+		}
+		recallSum += float64(hits) / float64(len(exact))
+		result.queries++
+	}
+
+	if result.queries > 0 {
+		result.recall = recallSum / float64(result.queries)
+	}
+	return result, nil
+}
+
+// percentile returns the p-th percentile (0..1) of the latencies, rounded to
+// the microsecond.
+func percentile(latencies []time.Duration, p float64) time.Duration {
+	if len(latencies) == 0 {
+		return 0
+	}
+	sorted := slices.Clone(latencies)
+	slices.Sort(sorted)
+	i := int(p * float64(len(sorted)-1))
+	return sorted[i].Round(time.Microsecond)
+}
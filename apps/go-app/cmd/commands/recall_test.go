@@ -0,0 +1,17 @@
+package commands
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunRecallEvaluation(t *testing.T) {
+	t.Run("Rejects a k or sample count below 1", func(t *testing.T) {
+		for _, args := range [][]string{{"-k", "0"}, {"-k", "-1"}, {"-samples", "0"}, {"-samples", "-1"}} {
+			err := runRecallEvaluation(args)
+
+			assert.ErrorContains(t, err, "usage: recall", args)
+		}
+	})
+}
@@ -24,6 +24,40 @@ func Execute(command string, args []string) error {
 		if err := runMigration(db, dir, args); err != nil {
 			return fmt.Errorf("migration failed: %w", err)
 		}
+	case "eval":
+		if err := runRelevanceEvaluation(args); err != nil {
+			return fmt.Errorf("relevance evaluation failed: %w", err)
+		}
+	case "recall":
+		if err := runRecallEvaluation(args); err != nil {
+			return fmt.Errorf("recall evaluation failed: %w", err)
+		}
+	case "chunk":
+		if err := runChunking(args); err != nil {
+			return fmt.Errorf("chunking failed: %w", err)
+		}
+	case "vocabulary":
+		if err := runVocabularyRefresh(); err != nil {
+			return fmt.Errorf("vocabulary refresh failed: %w", err)
+		}
+	case "signals":
+		if subcommand != "import" {
+			return errors.New("unknown signals subcommand: " + subcommand)
+		}
+		if err := runSignalImport(args[1:]); err != nil {
+			return fmt.Errorf("signal import failed: %w", err)
+		}
+	case "citations":
+		if subcommand != "import" {
+			return errors.New("unknown citations subcommand: " + subcommand)
+		}
+		if err := runCitationImport(args[1:]); err != nil {
+			return fmt.Errorf("citation import failed: %w", err)
+		}
+	case "authority":
+		if err := runAuthorityComputation(); err != nil {
+			return fmt.Errorf("authority computation failed: %w", err)
+		}
 	case "seed":
 		target := "all"
 		if subcommand != "" {
@@ -0,0 +1,81 @@
+package commands
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"flag"
+	"fmt"
+	"go-app/database"
+	"go-app/domain"
+	"go-app/internal/logging"
+	httpRepo "go-app/internal/repository/http"
+	"go-app/internal/repository/postgres"
+	"go-app/service"
+	"io"
+	"log/slog"
+	"os"
+	"strconv"
+)
+
+// runSignalImport replaces the stored values of a journal signal, citation
+// counts by default, by the ones of a CSV file of pmid,value rows, with an
+// optional header. Journals missing from the file lose the signal.
+func runSignalImport(args []string) error {
+	flags := flag.NewFlagSet("signals import", flag.ContinueOnError)
+	name := flags.String("name", domain.CitationCountSignal, "name of the signal")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 || *name == "" {
+		return errors.New("usage: signals import [-name signal] <file.csv>")
+	}
+
+	file, err := os.Open(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = 2
+	reader.ReuseRecord = true
+	var signals []domain.JournalSignal
+	for line := 1; ; line++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		pmid, pmidErr := strconv.ParseInt(record[0], 10, 64)
+		value, valueErr := strconv.ParseFloat(record[1], 64)
+		if pmidErr != nil || valueErr != nil {
+			if line == 1 {
+				continue // header
+			}
+			return fmt.Errorf("line %d: PMID must be an integer and value a number", line)
+		}
+		signals = append(signals, domain.JournalSignal{PMID: pmid, Name: *name, Value: value})
+	}
+
+	ctx := context.Background()
+	pool, err := database.SetupPgxPool()
+	if err != nil {
+		return err
+	}
+	defer pool.Close()
+
+	journalService := service.NewJournalService(
+		postgres.NewJournalRepository(pool),
+		httpRepo.NewEmbeddingHTTPRepository(),
+	)
+	if err := journalService.ImportJournalSignals(ctx, *name, signals); err != nil {
+		return err
+	}
+	logging.LogInfo(ctx, "Imported journal signals", slog.String("signal", *name), slog.Int("journals", len(signals)))
+
+	return nil
+}
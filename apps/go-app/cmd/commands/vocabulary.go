@@ -0,0 +1,26 @@
+package commands
+
+import (
+	"context"
+	"go-app/database"
+	"go-app/internal/logging"
+	"go-app/internal/repository/postgres"
+)
+
+// runVocabularyRefresh rebuilds the search vocabulary that fuzzy searches and
+// "did you mean" suggestions use, after journals were loaded or changed.
+func runVocabularyRefresh() error {
+	ctx := context.Background()
+	pool, err := database.SetupPgxPool()
+	if err != nil {
+		return err
+	}
+	defer pool.Close()
+
+	if err := postgres.NewJournalRepository(pool).RefreshSearchVocabulary(ctx); err != nil {
+		return err
+	}
+	logging.LogInfo(ctx, "Refreshed search vocabulary")
+
+	return nil
+}
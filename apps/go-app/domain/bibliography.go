@@ -0,0 +1,55 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// Author is an author of a journal, as listed by PubMed.
+type Author struct {
+	Name         string   `json:"name"` // display name, e.g. "Jane A Smith" or a collective name
+	LastName     string   `json:"last_name,omitempty"`
+	ForeName     string   `json:"fore_name,omitempty"`
+	Affiliations []string `json:"affiliations,omitempty"`
+}
+
+// Bibliography is the bibliographic metadata of a journal. Text fields are
+// empty when unknown.
+type Bibliography struct {
+	Authors          []Author `json:"authors"`          // in byline order
+	JournalName      string   `json:"journal_name"`     // title of the publishing journal
+	PublicationDate  string   `json:"publication_date"` // YYYY-MM-DD, day and month default to 1
+	DOI              string   `json:"doi"`
+	PMCID            string   `json:"pmcid"` // PubMed Central identifier, e.g. PMC1234567
+	PublicationTypes []string `json:"publication_types"`
+	Language         string   `json:"language"` // ISO 639-2 code, e.g. eng
+}
+
+// BibliographyFields are the fields of Bibliography a field selection can
+// pick.
+var BibliographyFields = []string{
+	"authors", "journal_name", "publication_date", "doi", "pmcid", "publication_types", "language",
+}
+
+// ParsePublicationDate parses a year (2019), a month (2019-03) or a day
+// (2019-03-14), returning the first day of that period and the first day
+// after it.
+func ParsePublicationDate(value string) (start, end time.Time, err error) {
+	for _, period := range []struct {
+		layout    string
+		addMonths int
+		addDays   int
+	}{
+		{"2006", 12, 0},
+		{"2006-01", 1, 0},
+		{"2006-01-02", 0, 1},
+	} {
+		if start, err = time.Parse(period.layout, value); err == nil {
+			return start, start.AddDate(0, period.addMonths, period.addDays), nil
+		}
+	}
+
+	return time.Time{}, time.Time{}, fmt.Errorf(
+		"%w: publication date %q must be YYYY, YYYY-MM or YYYY-MM-DD", ErrBadParamInput, value,
+	)
+}
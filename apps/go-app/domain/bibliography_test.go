@@ -0,0 +1,46 @@
+package domain_test
+
+import (
+	"go-app/domain"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJournalFilter_PublishedRange(t *testing.T) {
+	t.Run("Bounds include their whole period", func(t *testing.T) {
+		filter := &domain.JournalFilter{PublishedFrom: "2019-03", PublishedTo: "2020"}
+
+		from, before, err := filter.PublishedRange()
+
+		assert.NoError(t, err)
+		assert.Equal(t, time.Date(2019, 3, 1, 0, 0, 0, 0, time.UTC), *from)
+		assert.Equal(t, time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC), *before)
+	})
+
+	t.Run("Selects a single day", func(t *testing.T) {
+		filter := &domain.JournalFilter{PublishedFrom: "2019-03-14", PublishedTo: "2019-03-14"}
+
+		from, before, err := filter.PublishedRange()
+
+		assert.NoError(t, err)
+		assert.Equal(t, 24*time.Hour, before.Sub(*from))
+	})
+}
+
+func TestPickFields(t *testing.T) {
+	t.Run("Selects bibliographic fields", func(t *testing.T) {
+		filter := &domain.JournalFilter{Fields: "pmid,authors,doi"}
+		journal := domain.JournalResponse{PMID: 3}
+		journal.Authors = []domain.Author{{Name: "Jane A Smith", LastName: "Smith", ForeName: "Jane A"}}
+		journal.DOI = "10.1000/xyz123"
+
+		assert.NoError(t, filter.Validate())
+		picked, err := domain.PickFields([]domain.JournalResponse{journal}, filter.SelectedFields())
+		assert.NoError(t, err)
+		assert.Len(t, picked[0], 3)
+		assert.JSONEq(t, `"10.1000/xyz123"`, string(picked[0]["doi"]))
+		assert.JSONEq(t, `[{"name": "Jane A Smith", "last_name": "Smith", "fore_name": "Jane A"}]`, string(picked[0]["authors"]))
+	})
+}
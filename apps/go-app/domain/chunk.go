@@ -0,0 +1,34 @@
+package domain
+
+import "github.com/pgvector/pgvector-go"
+
+// Chunking bounds. Content is split on paragraphs, which are packed into
+// chunks of at most MaxChunkRunes characters; longer paragraphs are split on
+// sentence ends.
+const (
+	MaxChunkRunes = 1200
+	// A passage search ranks PassageCandidatesPerJournal chunks per journal
+	// up to the requested page, and at least DefaultPassageCandidates, before
+	// keeping the best chunk of each journal.
+	DefaultPassageCandidates    = 200
+	PassageCandidatesPerJournal = 4
+)
+
+// JournalChunk is a passage of the content of a journal. Start and End are
+// character (not byte) offsets into Journal.Content, End exclusive.
+type JournalChunk struct {
+	PMID       int64
+	Index      int
+	Text       string
+	Start      int
+	End        int
+	Embeddings map[VectorType]pgvector.Vector
+}
+
+// JournalPassage is the chunk a passage search matched a journal by.
+type JournalPassage struct {
+	Index int    `json:"index"`
+	Text  string `json:"text"`
+	Start int    `json:"start"`
+	End   int    `json:"end"`
+}
@@ -0,0 +1,76 @@
+package domain
+
+import "fmt"
+
+// JournalCitation is a reference from a journal of the corpus to another
+// journal, which may be missing from the corpus.
+type JournalCitation struct {
+	CitingPMID int64 `json:"citing_pmid"`
+	CitedPMID  int64 `json:"cited_pmid"`
+}
+
+// CitationRelation selects the neighbours of a journal in the citation graph.
+type CitationRelation string
+
+const (
+	// ReferencesRelation is the journals the journal cites.
+	ReferencesRelation CitationRelation = "references"
+	// CitedByRelation is the journals citing the journal.
+	CitedByRelation CitationRelation = "cited_by"
+	// CoCitedRelation is the journals cited together with the journal, by
+	// the number of journals citing both.
+	CoCitedRelation CitationRelation = "co_cited"
+)
+
+// CitationNeighbour is a journal linked to another by citations. Journals
+// missing from the corpus only have their PMID.
+type CitationNeighbour struct {
+	PMID            int64  `json:"pmid"`
+	InCorpus        bool   `json:"in_corpus"`
+	Title           string `json:"title"`
+	JournalName     string `json:"journal_name"`
+	PublicationDate string `json:"publication_date"`
+	// Authority is the PageRank of the journal in the citation graph, see
+	// AuthoritySignal, or 0 when it was not computed.
+	Authority float64 `json:"authority"`
+	// CoCitations is the number of journals citing both, for co-citation
+	// neighbours.
+	CoCitations int64 `json:"co_citations,omitempty"`
+}
+
+const (
+	DefaultCitationLimit = 20
+	MaxCitationLimit     = 100
+)
+
+// CitationFilter pages through the neighbours of a journal, the most
+// authoritative first (the most co-cited first for co-citations).
+type CitationFilter struct {
+	Limit *int `json:"limit" query:"limit"`
+	Page  *int `json:"page" query:"page"`
+}
+
+func (f *CitationFilter) Validate() error {
+	if f.Limit != nil && (*f.Limit < 1 || *f.Limit > MaxCitationLimit) {
+		return fmt.Errorf("%w: limit must be between 1 and %d", ErrBadParamInput, MaxCitationLimit)
+	}
+	if f.Page != nil && *f.Page < 0 {
+		return fmt.Errorf("%w: page must not be negative", ErrBadParamInput)
+	}
+
+	return nil
+}
+
+// CitationList is a page of the neighbours of a journal.
+type CitationList struct {
+	Neighbours []CitationNeighbour
+	Meta       ListMeta
+}
+
+// PageRank parameters of the authority score. Iterations stop once the
+// scores change by less than PageRankTolerance in total.
+const (
+	PageRankDamping       = 0.85
+	PageRankTolerance     = 1e-9
+	MaxPageRankIterations = 100
+)
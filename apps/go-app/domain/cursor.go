@@ -0,0 +1,36 @@
+package domain
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+)
+
+// JournalCursor is the keyset position of the last journal of a page. Score is
+// only set for ranked searches, which are ordered by score and then PMID;
+// plain listings are ordered by PMID alone.
+type JournalCursor struct {
+	Score *float64 `json:"s,omitempty"`
+	PMID  int64    `json:"p"`
+}
+
+// Encode returns the cursor as an opaque URL-safe token.
+func (c JournalCursor) Encode() string {
+	b, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// DecodeJournalCursor parses a token produced by JournalCursor.Encode.
+func DecodeJournalCursor(token string) (*JournalCursor, error) {
+	b, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed cursor", ErrBadParamInput)
+	}
+
+	var c JournalCursor
+	if err := json.Unmarshal(b, &c); err != nil {
+		return nil, fmt.Errorf("%w: malformed cursor", ErrBadParamInput)
+	}
+
+	return &c, nil
+}
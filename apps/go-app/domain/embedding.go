@@ -12,3 +12,14 @@ type EmbeddingOutput struct {
 	Message string          `json:"message"`
 	Data    pgvector.Vector `json:"data"`
 }
+
+type RerankInput struct {
+	Query     string   `json:"query"`
+	Documents []string `json:"documents"`
+}
+
+type RerankOutput struct {
+	Success bool      `json:"success"`
+	Message string    `json:"message"`
+	Data    []float64 `json:"data"`
+}
@@ -11,6 +11,8 @@ var (
 	ErrConflict = errors.New("your Item already exist")
 	// ErrBadParamInput will throw if the given request-body or params is not valid
 	ErrBadParamInput = errors.New("given Param is not valid")
+	// ErrPassagesNotIndexed will throw if a passage search runs before journals were chunked
+	ErrPassagesNotIndexed = errors.New("passages not indexed; run the chunk command")
 	// ErrUserNotFound
 	ErrUserNotFound = errors.New("user not found")
 )
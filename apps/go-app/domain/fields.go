@@ -0,0 +1,71 @@
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// JournalFields are the fields of Journal a field selection can pick.
+var JournalFields = append(
+	[]string{"pmid", "title", "abstract", "content", "mesh_terms"},
+	BibliographyFields...,
+)
+
+// JournalResponseFields are the fields of JournalResponse a field selection
+// can pick.
+var JournalResponseFields = append(
+	slices.Clone(JournalFields),
+	"distance", "lexical_score", "score_type", "passage", "highlights", "score_components",
+)
+
+// ParseFields parses a comma separated field selection such as
+// "pmid,title,distance", checking that every field is one of allowed. An
+// empty selection returns nil, which selects every field.
+func ParseFields(fields string, allowed []string) ([]string, error) {
+	if strings.TrimSpace(fields) == "" {
+		return nil, nil
+	}
+
+	var selected []string
+	for _, field := range strings.Split(fields, ",") {
+		field = strings.TrimSpace(field)
+		if !slices.Contains(allowed, field) {
+			return nil, fmt.Errorf("%w: unknown field %q", ErrBadParamInput, field)
+		}
+		if !slices.Contains(selected, field) {
+			selected = append(selected, field)
+		}
+	}
+
+	return selected, nil
+}
+
+// HasField reports whether the field selection includes field.
+func HasField(fields []string, field string) bool {
+	return fields == nil || slices.Contains(fields, field)
+}
+
+// PickFields reduces the JSON object of every item to the selected fields.
+func PickFields[T any](items []T, fields []string) ([]map[string]json.RawMessage, error) {
+	picked := make([]map[string]json.RawMessage, len(items))
+	for i, item := range items {
+		raw, err := json.Marshal(item)
+		if err != nil {
+			return nil, err
+		}
+		var object map[string]json.RawMessage
+		if err := json.Unmarshal(raw, &object); err != nil {
+			return nil, err
+		}
+		for key := range object {
+			if !HasField(fields, key) {
+				delete(object, key)
+			}
+		}
+		picked[i] = object
+	}
+
+	return picked, nil
+}
@@ -1,6 +1,10 @@
 package domain
 
 import (
+	"fmt"
+	"slices"
+	"time"
+
 	"github.com/pgvector/pgvector-go"
 )
 
@@ -10,6 +14,7 @@ type Journal struct {
 	Abstract  string   `json:"abstract"`
 	Content   string   `json:"content"`
 	MeSHTerms []string `json:"mesh_terms"`
+	Bibliography
 }
 
 type JournalEmbedding struct {
@@ -18,12 +23,78 @@ type JournalEmbedding struct {
 }
 
 type JournalResponse struct {
-	PMID      int64    `json:"pmid"`
-	Title     string   `json:"title"`
-	Abstract  string   `json:"abstract"`
-	Content   string   `json:"content"`
-	MeSHTerms []string `json:"mesh_terms"`
-	Distance  float64  `json:"distance"`
+	PMID         int64     `json:"pmid"`
+	Title        string    `json:"title"`
+	Abstract     string    `json:"abstract"`
+	Content      string    `json:"content,omitempty"` // empty when the filter omits content
+	MeSHTerms    []string  `json:"mesh_terms"`
+	Distance     float64   `json:"distance"`          // primary ranking score, higher is better; see ScoreType
+	LexicalScore float64   `json:"lexical_score"`     // ts_rank_cd of the search query, 0 without search
+	ScoreType    ScoreType `json:"score_type" db:"-"` // what distance measures
+	// Passage is the best-matching chunk of the content of passage searches.
+	Passage *JournalPassage `json:"passage,omitempty"`
+	// Highlights are the snippets matching the query when it asks for them.
+	Highlights *JournalHighlights `json:"highlights,omitempty" db:"-"`
+	Bibliography
+	// ScoreComponents break the distance of boosted rankings down.
+	ScoreComponents []ScoreComponent `json:"score_components,omitempty" db:"-"`
+}
+
+// JournalHighlights holds HTML snippets of the fields of a journal that match
+// the search query, with the matched terms wrapped in <mark> and the rest of
+// the text escaped. Fields without a match are left empty.
+type JournalHighlights struct {
+	PMID     int64    `json:"-"`
+	Title    string   `json:"title,omitempty"`
+	Abstract string   `json:"abstract,omitempty"`
+	Content  []string `json:"content,omitempty"` // up to MaxContentHighlights fragments
+}
+
+// MaxContentHighlights bounds the number of content fragments highlighted.
+const MaxContentHighlights = 3
+
+// ScoreType documents the meaning of JournalResponse.Distance.
+type ScoreType string
+
+const (
+	// NoScore is used for plain listings ordered by PMID; distance is 0.
+	NoScore ScoreType = "none"
+	// LexicalRankScore is the ts_rank_cd of the search query, unbounded above.
+	LexicalRankScore ScoreType = "ts_rank_cd"
+	// CosineSimilarityScore is 1 - cosine distance, in [-1, 1].
+	CosineSimilarityScore ScoreType = "cosine_similarity"
+	// NegativeL2DistanceScore is the Euclidean distance negated, so that
+	// higher is better like every other score; at most 0.
+	NegativeL2DistanceScore ScoreType = "negative_l2_distance"
+	// InnerProductScore is the dot product of the query and journal vectors.
+	InnerProductScore ScoreType = "inner_product"
+	// RRFScore is the reciprocal rank fusion score of a hybrid search.
+	RRFScore ScoreType = "rrf"
+	// WeightedFusionScore is the weighted sum of the min-max normalized
+	// lexical and vector scores of a hybrid search.
+	WeightedFusionScore ScoreType = "weighted_fusion"
+	// CrossEncoderScore is the relevance logit of the re-ranking cross-encoder,
+	// unbounded in both directions.
+	CrossEncoderScore ScoreType = "cross_encoder"
+	// TrigramSimilarityScore is the pg_trgm word similarity of a fuzzy
+	// search to the title or MeSH headings, in [0, 1].
+	TrigramSimilarityScore ScoreType = "trigram_similarity"
+	// BoostedScore is the relevance of a search boosted by a ranking profile,
+	// broken down in JournalResponse.ScoreComponents.
+	BoostedScore ScoreType = "boosted"
+)
+
+// JournalCandidate is a search result together with its stored embedding,
+// for re-ordering that compares results with each other.
+type JournalCandidate struct {
+	JournalResponse
+	Embedding pgvector.Vector `db:"embedding"`
+}
+
+// JournalList is a page of journals together with its pagination metadata.
+type JournalList struct {
+	Journals []JournalResponse
+	Meta     ListMeta
 }
 
 type VectorType string
@@ -31,12 +102,578 @@ type VectorType string
 const (
 	GeneralVectorType    VectorType = "generalist"
 	SpecialistVectorType VectorType = "specialist"
+	// EnsembleVectorType searches the embeddings of every model and fuses
+	// their rankings, weighted by JournalFilter.GeneralistWeight and
+	// JournalFilter.SpecialistWeight.
+	EnsembleVectorType VectorType = "ensemble"
+)
+
+// Models returns the vector types whose embeddings a search over v uses.
+func (v VectorType) Models() []VectorType {
+	if v == EnsembleVectorType {
+		return []VectorType{GeneralVectorType, SpecialistVectorType}
+	}
+	return []VectorType{v}
+}
+
+// QueryEmbeddings holds the search query embedded by each model of a vector
+// type. It is nil when the search does not use vectors.
+type QueryEmbeddings map[VectorType]pgvector.Vector
+
+// DistanceMetric is the similarity an embedding model was trained for. The
+// HNSW index of the model's embedding table must be built with the matching
+// operator class (vector_cosine_ops, vector_l2_ops or vector_ip_ops), or
+// searches fall back to a sequential scan.
+type DistanceMetric string
+
+const (
+	CosineMetric       DistanceMetric = "cosine"
+	L2Metric           DistanceMetric = "l2"
+	InnerProductMetric DistanceMetric = "inner_product"
+)
+
+// vectorMetrics declares the distance metric of each embedding model. It
+// must match the operator class of the HNSW indexes of the model's embedding
+// table and journal_chunks column, see the migrations.
+var vectorMetrics = map[VectorType]DistanceMetric{
+	GeneralVectorType:    CosineMetric,
+	SpecialistVectorType: CosineMetric,
+}
+
+// Metric returns the distance metric the embeddings of the vector type are
+// indexed and searched with. It panics for a type without a declared metric,
+// such as EnsembleVectorType, whose searches use the metrics of its Models.
+func (v VectorType) Metric() DistanceMetric {
+	metric, ok := vectorMetrics[v]
+	if !ok {
+		panic(fmt.Sprintf("domain: vector type %q has no distance metric", v))
+	}
+	return metric
+}
+
+// ScoreType returns the score reported for searches with the metric.
+func (m DistanceMetric) ScoreType() ScoreType {
+	switch m {
+	case L2Metric:
+		return NegativeL2DistanceScore
+	case InnerProductMetric:
+		return InnerProductScore
+	}
+	return CosineSimilarityScore
+}
+
+// ScoreType returns the score reported for searches over the vector type.
+func (v VectorType) ScoreType() ScoreType {
+	return v.Metric().ScoreType()
+}
+
+// FusionMethod selects how the lexical and vector candidate lists of a
+// hybrid search are merged into a single ranking.
+type FusionMethod string
+
+const (
+	// RRFFusion scores each journal by reciprocal rank fusion:
+	// sum(weight / (k + rank)) over the lists it appears in.
+	RRFFusion FusionMethod = "rrf"
+	// WeightedFusion scores each journal by the weighted sum of its
+	// min-max normalized lexical and vector scores.
+	WeightedFusion FusionMethod = "weighted"
+)
+
+const (
+	DefaultRRFK             = 60
+	DefaultHybridCandidates = 100
+)
+
+// RecallProfile trades latency for recall of the HNSW index scans of a
+// vector search.
+type RecallProfile string
+
+const (
+	FastRecall       RecallProfile = "fast"
+	BalancedRecall   RecallProfile = "balanced"
+	ExhaustiveRecall RecallProfile = "exhaustive"
+	// ExactRecall bypasses the HNSW index for a brute-force search. It is
+	// selected with JournalFilter.Exact rather than by name.
+	ExactRecall RecallProfile = "exact"
+)
+
+// HNSWSettings are the pgvector settings applied to the transaction of a
+// vector search.
+type HNSWSettings struct {
+	EfSearch int // hnsw.ef_search, candidates kept per index scan
+	// IterativeScan is hnsw.iterative_scan: "off", or "strict_order" to keep
+	// scanning the index until filtered searches have enough rows.
+	IterativeScan string
+	MaxScanTuples int  // hnsw.max_scan_tuples, bound of an iterative scan
+	Exact         bool // disables index scans so every vector is compared
+}
+
+// HNSWSettings returns the index scan settings of the profile.
+func (p RecallProfile) HNSWSettings() HNSWSettings {
+	switch p {
+	case FastRecall:
+		return HNSWSettings{EfSearch: 40, IterativeScan: "off", MaxScanTuples: 20000}
+	case ExhaustiveRecall:
+		return HNSWSettings{EfSearch: 400, IterativeScan: "strict_order", MaxScanTuples: 200000}
+	case ExactRecall:
+		return HNSWSettings{Exact: true}
+	}
+	return HNSWSettings{EfSearch: 100, IterativeScan: "strict_order", MaxScanTuples: 20000}
+}
+
+// Re-ranking bounds. The depth is the number of first-stage candidates scored
+// by the cross-encoder, and pages past it are empty.
+const (
+	DefaultRerankDepth = 50
+	MaxRerankDepth     = 200
+)
+
+// Maximal marginal relevance defaults. Lambda weighs relevance against
+// novelty: 1 keeps the relevance order and 0 only maximizes novelty. Results
+// are picked from at least DefaultMMRCandidates candidates.
+const (
+	DefaultMMRLambda     = 0.5
+	DefaultMMRCandidates = 100
 )
 
+// CountMode selects whether and how the total number of matches is counted.
+type CountMode string
+
+const (
+	NoCount        CountMode = "none"
+	EstimatedCount CountMode = "estimate" // planner row estimate, cheap
+	ExactCount     CountMode = "exact"    // count(*), expensive on vector queries
+)
+
+// MaxJournalLimit is the largest page size of a journal listing.
+const MaxJournalLimit = 100
+
 type JournalFilter struct {
-	Limit   *int       `json:"limit" query:"limit"`
-	Page    *int       `json:"page" query:"page"`
-	Search  string     `json:"search" query:"search"`
-	VSearch string     `json:"v_search" query:"v_search"`
-	Type    VectorType `json:"type" query:"type"`
+	Limit         *int          `json:"limit" query:"limit"`
+	Page          *int          `json:"page" query:"page"`
+	Search        string        `json:"search" query:"search"` // boolean query, see ParseSearchQuery
+	Fuzzy         bool          `json:"fuzzy" query:"fuzzy"`   // matches search to titles and MeSH headings by trigrams
+	VSearch       string        `json:"v_search" query:"v_search"`
+	Type          VectorType    `json:"type" query:"type"`
+	Fusion        FusionMethod  `json:"fusion" query:"fusion"`
+	LexicalWeight *float64      `json:"lexical_weight" query:"lexical_weight"`
+	VectorWeight  *float64      `json:"vector_weight" query:"vector_weight"`
+	RRFK          *int          `json:"rrf_k" query:"rrf_k"`
+	Cursor        string        `json:"cursor" query:"cursor"`             // takes precedence over page
+	Count         CountMode     `json:"count" query:"count"`               // defaults to none
+	MeSHAny       []string      `json:"mesh_any" query:"mesh_any"`         // has at least one of the terms
+	MeSHAll       []string      `json:"mesh_all" query:"mesh_all"`         // has every one of the terms
+	MeSHNone      []string      `json:"mesh_none" query:"mesh_none"`       // has none of the terms
+	MinScore      *float64      `json:"min_score" query:"min_score"`       // drops results whose distance is lower
+	Recall        RecallProfile `json:"recall" query:"recall"`             // vector searches default to balanced
+	Exact         bool          `json:"exact" query:"exact"`               // brute-force vector search, overrides recall
+	Rerank        bool          `json:"rerank" query:"rerank"`             // re-scores the top candidates with a cross-encoder
+	RerankDepth   *int          `json:"rerank_depth" query:"rerank_depth"` // candidates re-scored, min_score prunes them first
+	MMR           bool          `json:"mmr" query:"mmr"`                   // diversifies vector results by maximal marginal relevance
+	Lambda        *float64      `json:"lambda" query:"lambda"`             // MMR relevance weight in [0, 1]
+	// Weights of each model's ranking in an ensemble search, default 1.
+	GeneralistWeight *float64 `json:"generalist_weight" query:"generalist_weight"`
+	SpecialistWeight *float64 `json:"specialist_weight" query:"specialist_weight"`
+	// Passages ranks journals by their best-matching content chunk instead
+	// of their whole-journal embedding, and returns that chunk.
+	Passages bool `json:"passages" query:"passages"`
+	// Highlight adds snippets of the matching fields, see JournalHighlights.
+	Highlight   bool `json:"highlight" query:"highlight"`
+	OmitContent bool `json:"omit_content" query:"omit_content"` // leaves out the full article body
+	// Fields is a comma separated selection of JournalResponseFields, every
+	// field when empty.
+	Fields string `json:"fields" query:"fields"`
+	// Bibliographic filters. Publication dates are a year, a month or a day
+	// (see ParsePublicationDate) and both bounds include their whole period.
+	PublishedFrom    string   `json:"published_from" query:"published_from"`
+	PublishedTo      string   `json:"published_to" query:"published_to"`
+	Author           string   `json:"author" query:"author"`                       // full or last name, case-insensitive
+	JournalName      string   `json:"journal_name" query:"journal_name"`           // case-insensitive
+	PublicationTypes []string `json:"publication_types" query:"publication_types"` // has at least one of the types
+	// Ranking selects the boosts of the relevance, whose weights and
+	// half-life (in years) can be overridden; see RankingSettings.
+	Ranking         RankingProfile `json:"ranking" query:"ranking"`
+	RecencyWeight   *float64       `json:"recency_weight" query:"recency_weight"`
+	RecencyHalfLife *float64       `json:"recency_half_life" query:"recency_half_life"`
+	CitationWeight  *float64       `json:"citation_weight" query:"citation_weight"`
+	AuthorityWeight *float64       `json:"authority_weight" query:"authority_weight"`
+}
+
+// IsHybrid reports whether the filter asks for lexical and vector results to
+// be retrieved independently and fused, instead of the lexical search only
+// pruning the vector candidates.
+func (f *JournalFilter) IsHybrid() bool {
+	return f != nil && f.Fusion != "" && f.Search != "" && f.VSearch != ""
+}
+
+// IsFused reports whether several candidate lists are retrieved and fused
+// into one ranking, either by a hybrid search or by the ensemble vector type.
+func (f *JournalFilter) IsFused() bool {
+	return f.IsHybrid() || (f != nil && f.VSearch != "" && f.Type == EnsembleVectorType)
+}
+
+// ModelWeight returns the weight of the ranking of model within an ensemble
+// search, defaulting to 1.
+func (f *JournalFilter) ModelWeight(model VectorType) float64 {
+	var weight *float64
+	switch model {
+	case GeneralVectorType:
+		weight = f.GeneralistWeight
+	case SpecialistVectorType:
+		weight = f.SpecialistWeight
+	}
+	if weight == nil {
+		return 1
+	}
+	return *weight
+}
+
+// IsRanked reports whether results are ordered by a relevance score instead
+// of by PMID.
+func (f *JournalFilter) IsRanked() bool {
+	return f != nil && (f.Search != "" || f.VSearch != "")
+}
+
+// ScoreType reports how the distance of the journals matched by the filter
+// is computed.
+func (f *JournalFilter) ScoreType() ScoreType {
+	if f.IsBoosted() {
+		return BoostedScore
+	}
+	return f.RelevanceScoreType()
+}
+
+// RelevanceScoreType reports how the relevance of the journals matched by the
+// filter is computed, before any ranking boost.
+func (f *JournalFilter) RelevanceScoreType() ScoreType {
+	switch {
+	case f != nil && f.Rerank:
+		return CrossEncoderScore
+	case f.IsFused() && f.Fusion == WeightedFusion:
+		return WeightedFusionScore
+	case f.IsFused():
+		return RRFScore
+	case f != nil && f.VSearch != "":
+		return f.Type.ScoreType()
+	case f != nil && f.Search != "" && f.Fuzzy:
+		return TrigramSimilarityScore
+	case f != nil && f.Search != "":
+		return LexicalRankScore
+	}
+	return NoScore
+}
+
+// RecallProfile returns the HNSW recall profile of a vector search, or an
+// empty profile when the filter does not search vectors.
+func (f *JournalFilter) RecallProfile() RecallProfile {
+	if f == nil || f.VSearch == "" {
+		return ""
+	}
+	if f.Exact {
+		return ExactRecall
+	}
+	if f.Recall == "" {
+		return BalancedRecall
+	}
+	return f.Recall
+}
+
+// After decodes the cursor of the filter, returning nil when none is set.
+func (f *JournalFilter) After() (*JournalCursor, error) {
+	if f == nil || f.Cursor == "" {
+		return nil, nil
+	}
+
+	c, err := DecodeJournalCursor(f.Cursor)
+	if err != nil {
+		return nil, err
+	}
+	if f.IsRanked() != (c.Score != nil) {
+		return nil, fmt.Errorf("%w: cursor does not belong to this query", ErrBadParamInput)
+	}
+
+	return c, nil
+}
+
+// NextCursor returns the cursor of the page following journals, or an empty
+// string when journals is shorter than the page size and so the last page, or
+// when the search does not support cursors.
+func (f *JournalFilter) NextCursor(journals []JournalResponse) string {
+	if f == nil || f.Limit == nil || len(journals) == 0 || len(journals) < *f.Limit {
+		return ""
+	}
+	if f.IsFused() || f.Passages {
+		return ""
+	}
+
+	last := journals[len(journals)-1]
+	c := JournalCursor{PMID: last.PMID}
+	if f.IsRanked() {
+		score := last.Distance
+		if f.VSearch == "" {
+			score = last.LexicalScore
+		}
+		c.Score = &score
+	}
+
+	return c.Encode()
+}
+
+func (f *JournalFilter) Validate() error {
+	if f == nil {
+		return nil
+	}
+
+	if f.Limit != nil && (*f.Limit < 1 || *f.Limit > MaxJournalLimit) {
+		return fmt.Errorf("%w: limit must be between 1 and %d", ErrBadParamInput, MaxJournalLimit)
+	}
+	if f.Page != nil && *f.Page < 0 {
+		return fmt.Errorf("%w: page must not be negative", ErrBadParamInput)
+	}
+	if f.Search != "" {
+		if _, err := ParseSearchQuery(f.Search); err != nil {
+			return err
+		}
+	}
+	if _, _, err := f.PublishedRange(); err != nil {
+		return err
+	}
+	if f.Fuzzy && f.SearchKeywords() == "" {
+		return fmt.Errorf("%w: fuzzy requires search with words to match", ErrBadParamInput)
+	}
+	switch {
+	case f.VSearch == "":
+	case f.Type == GeneralVectorType, f.Type == SpecialistVectorType, f.Type == EnsembleVectorType:
+	default:
+		return fmt.Errorf("%w: unknown vector type %q", ErrBadParamInput, f.Type)
+	}
+	if f.GeneralistWeight != nil && *f.GeneralistWeight < 0 {
+		return fmt.Errorf("%w: generalist_weight must not be negative", ErrBadParamInput)
+	}
+	if f.SpecialistWeight != nil && *f.SpecialistWeight < 0 {
+		return fmt.Errorf("%w: specialist_weight must not be negative", ErrBadParamInput)
+	}
+	switch f.Fusion {
+	case "", RRFFusion, WeightedFusion:
+	default:
+		return fmt.Errorf("%w: unknown fusion method %q", ErrBadParamInput, f.Fusion)
+	}
+	if f.LexicalWeight != nil && *f.LexicalWeight < 0 {
+		return fmt.Errorf("%w: lexical_weight must not be negative", ErrBadParamInput)
+	}
+	if f.VectorWeight != nil && *f.VectorWeight < 0 {
+		return fmt.Errorf("%w: vector_weight must not be negative", ErrBadParamInput)
+	}
+	if f.RRFK != nil && *f.RRFK < 1 {
+		return fmt.Errorf("%w: rrf_k must be at least 1", ErrBadParamInput)
+	}
+	if f.MinScore != nil && !f.IsRanked() {
+		return fmt.Errorf("%w: min_score requires search or v_search", ErrBadParamInput)
+	}
+	switch f.Recall {
+	case "", FastRecall, BalancedRecall, ExhaustiveRecall:
+	default:
+		return fmt.Errorf("%w: unknown recall profile %q", ErrBadParamInput, f.Recall)
+	}
+	switch f.Count {
+	case "", NoCount, EstimatedCount, ExactCount:
+	default:
+		return fmt.Errorf("%w: unknown count mode %q", ErrBadParamInput, f.Count)
+	}
+	if f.Rerank && !f.IsRanked() {
+		return fmt.Errorf("%w: rerank requires search or v_search", ErrBadParamInput)
+	}
+	if f.Rerank && f.Cursor != "" {
+		return fmt.Errorf("%w: rerank does not support cursor, use page", ErrBadParamInput)
+	}
+	if f.RerankDepth != nil && (*f.RerankDepth < 1 || *f.RerankDepth > MaxRerankDepth) {
+		return fmt.Errorf("%w: rerank_depth must be between 1 and %d", ErrBadParamInput, MaxRerankDepth)
+	}
+	if f.MMR && f.VSearch == "" {
+		return fmt.Errorf("%w: mmr requires v_search", ErrBadParamInput)
+	}
+	if f.MMR && f.Rerank {
+		return fmt.Errorf("%w: mmr cannot be combined with rerank", ErrBadParamInput)
+	}
+	if f.MMR && f.Cursor != "" {
+		return fmt.Errorf("%w: mmr does not support cursor, use page", ErrBadParamInput)
+	}
+	if _, err := ParseFields(f.Fields, JournalResponseFields); err != nil {
+		return err
+	}
+	if f.Highlight && !f.IsRanked() {
+		return fmt.Errorf("%w: highlight requires search or v_search", ErrBadParamInput)
+	}
+	if f.Passages && (f.VSearch == "" || f.IsFused()) {
+		return fmt.Errorf("%w: passages requires v_search with a single model and no fusion", ErrBadParamInput)
+	}
+	if f.Cursor != "" && (f.IsFused() || f.Passages) {
+		// Their candidate pools are sized from the page, which a cursor does
+		// not give, see DefaultHybridCandidates.
+		return fmt.Errorf("%w: fused and passage searches do not support cursor, use page", ErrBadParamInput)
+	}
+	if f.Lambda != nil && (*f.Lambda < 0 || *f.Lambda > 1) {
+		return fmt.Errorf("%w: lambda must be between 0 and 1", ErrBadParamInput)
+	}
+	if err := f.validateRanking(); err != nil {
+		return err
+	}
+	if _, err := f.After(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// PublishedRange returns the publication dates the filter selects, from
+// inclusive and before exclusive, each nil when unbounded.
+func (f *JournalFilter) PublishedRange() (from, before *time.Time, err error) {
+	if f == nil {
+		return nil, nil, nil
+	}
+	if f.PublishedFrom != "" {
+		start, _, err := ParsePublicationDate(f.PublishedFrom)
+		if err != nil {
+			return nil, nil, err
+		}
+		from = &start
+	}
+	if f.PublishedTo != "" {
+		_, end, err := ParsePublicationDate(f.PublishedTo)
+		if err != nil {
+			return nil, nil, err
+		}
+		before = &end
+	}
+	if from != nil && before != nil && !from.Before(*before) {
+		return nil, nil, fmt.Errorf("%w: published_from must not be after published_to", ErrBadParamInput)
+	}
+
+	return from, before, nil
+}
+
+// RerankQuery returns the text the cross-encoder compares passages against,
+// preferring the natural language v_search over the keywords of the search.
+func (f *JournalFilter) RerankQuery() string {
+	if f.VSearch != "" {
+		return f.VSearch
+	}
+	if keywords := f.SearchKeywords(); keywords != "" {
+		return keywords
+	}
+	return f.Search
+}
+
+// SearchKeywords returns the words and phrases of the search that are not
+// negated, see SearchNode.Keywords, or an empty string without any.
+func (f *JournalFilter) SearchKeywords() string {
+	if f == nil || f.Search == "" {
+		return ""
+	}
+	query, err := ParseSearchQuery(f.Search)
+	if err != nil {
+		return ""
+	}
+	return query.Keywords()
+}
+
+// SelectedFields returns the fields of the results to return, or nil for
+// every field.
+func (f *JournalFilter) SelectedFields() []string {
+	if f == nil {
+		return nil
+	}
+
+	fields, _ := ParseFields(f.Fields, JournalResponseFields)
+	if f.OmitContent {
+		if fields == nil {
+			fields = slices.Clone(JournalResponseFields)
+		}
+		fields = slices.DeleteFunc(fields, func(field string) bool { return field == "content" })
+	}
+	return fields
+}
+
+// HighlightQuery returns the text whose terms are highlighted, preferring the
+// keywords of the lexical search over the natural language v_search.
+func (f *JournalFilter) HighlightQuery() string {
+	if f.Search != "" {
+		return f.Search
+	}
+	return f.VSearch
+}
+
+// SimilarJournalFilter selects the embedding space and page size used to find
+// the nearest neighbours of an existing journal.
+type SimilarJournalFilter struct {
+	Limit    *int       `json:"limit" query:"limit"`
+	Type     VectorType `json:"type" query:"type"`
+	MinScore *float64   `json:"min_score" query:"min_score"` // minimum score, see VectorType.ScoreType
+}
+
+func (f *SimilarJournalFilter) Validate() error {
+	if f.Type != GeneralVectorType && f.Type != SpecialistVectorType {
+		return fmt.Errorf("%w: unknown vector type %q", ErrBadParamInput, f.Type)
+	}
+	if f.Limit != nil && *f.Limit < 1 {
+		return fmt.Errorf("%w: limit must be at least 1", ErrBadParamInput)
+	}
+
+	return nil
+}
+
+// MaxBatchGetPMIDs bounds the number of PMIDs resolved by one batch request.
+const MaxBatchGetPMIDs = 200
+
+type JournalBatchGetRequest struct {
+	PMIDs []int64 `json:"pmids"`
+}
+
+func (r *JournalBatchGetRequest) Validate() error {
+	if len(r.PMIDs) == 0 {
+		return fmt.Errorf("%w: pmids must not be empty", ErrBadParamInput)
+	}
+	if len(r.PMIDs) > MaxBatchGetPMIDs {
+		return fmt.Errorf("%w: at most %d pmids can be requested at once", ErrBadParamInput, MaxBatchGetPMIDs)
+	}
+
+	return nil
+}
+
+type JournalBatchGetResponse struct {
+	Journals []Journal `json:"journals"` // found journals, in request order
+	Missing  []int64   `json:"missing"`  // requested PMIDs that do not exist
+}
+
+const (
+	DefaultFacetSize  = 20
+	MaxFacetSize      = 100
+	DefaultFacetDepth = 200
+	MaxFacetDepth     = 1000
+)
+
+// JournalFacetFilter selects the result set to facet with the embedded
+// JournalFilter. Lexical and plain queries facet every match, while vector
+// and hybrid queries only facet their Depth best candidates.
+type JournalFacetFilter struct {
+	JournalFilter
+	Size  *int `json:"size" query:"size"`   // number of terms returned
+	Depth *int `json:"depth" query:"depth"` // candidates faceted for vector queries
+}
+
+func (f *JournalFacetFilter) Validate() error {
+	if f.Size != nil && (*f.Size < 1 || *f.Size > MaxFacetSize) {
+		return fmt.Errorf("%w: size must be between 1 and %d", ErrBadParamInput, MaxFacetSize)
+	}
+	if f.Depth != nil && (*f.Depth < 1 || *f.Depth > MaxFacetDepth) {
+		return fmt.Errorf("%w: depth must be between 1 and %d", ErrBadParamInput, MaxFacetDepth)
+	}
+
+	return f.JournalFilter.Validate()
+}
+
+type FacetCount struct {
+	Value string `json:"value"`
+	Count int64  `json:"count"`
 }
@@ -0,0 +1,22 @@
+package domain_test
+
+import (
+	"go-app/domain"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVectorType_Metric(t *testing.T) {
+	t.Run("Every model declares its metric", func(t *testing.T) {
+		for _, model := range domain.EnsembleVectorType.Models() {
+			assert.NotPanics(t, func() { model.Metric() }, model)
+			assert.Equal(t, model.Metric().ScoreType(), model.ScoreType(), model)
+		}
+	})
+
+	t.Run("Panics for a type without a metric", func(t *testing.T) {
+		assert.Panics(t, func() { domain.EnsembleVectorType.Metric() })
+		assert.Panics(t, func() { domain.VectorType("unknown").Metric() })
+	})
+}
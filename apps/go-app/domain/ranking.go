@@ -0,0 +1,229 @@
+package domain
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// Journal signals are numeric values stored per journal in journal_signals,
+// which ranking profiles can boost by.
+const (
+	// CitationCountSignal is the number of times the journal is cited.
+	CitationCountSignal = "citation_count"
+	// AuthoritySignal is the PageRank of the journal in the citation graph,
+	// scaled so that its mean over the graph is 1.
+	AuthoritySignal = "authority"
+)
+
+// Pivots of the signals, at which their boost is half its weight.
+const (
+	CitationPivot  = 50
+	AuthorityPivot = 1
+)
+
+// DefaultRecencyHalfLife is the recency half-life of the ranking profiles, in
+// years.
+const DefaultRecencyHalfLife = 5.0
+
+// JournalSignal is a numeric signal of a journal.
+type JournalSignal struct {
+	PMID  int64   `json:"pmid"`
+	Name  string  `json:"name"`
+	Value float64 `json:"value"`
+}
+
+// Validate checks that the signal is named and that its value is a
+// non-negative number, which boosts saturate.
+func (s JournalSignal) Validate() error {
+	if s.Name == "" {
+		return fmt.Errorf("%w: signal name is required", ErrBadParamInput)
+	}
+	if s.Value < 0 || math.IsNaN(s.Value) || math.IsInf(s.Value, 0) {
+		return fmt.Errorf("%w: %s of journal %d must be a non-negative number", ErrBadParamInput, s.Name, s.PMID)
+	}
+
+	return nil
+}
+
+// RankingProfile selects the boosts combined with the relevance of a search
+// to rank its results.
+type RankingProfile string
+
+const (
+	// RelevanceRanking ranks by relevance alone, the default.
+	RelevanceRanking RankingProfile = "relevance"
+	// RecentRanking favours recent publications.
+	RecentRanking RankingProfile = "recent"
+	// CitedRanking favours frequently cited journals.
+	CitedRanking RankingProfile = "cited"
+	// ClinicalRanking favours recent publications first and frequently cited
+	// journals second, for clinical questions where current guidelines
+	// matter more than older papers with similar wording.
+	ClinicalRanking RankingProfile = "clinical"
+	// AuthoritativeRanking favours journals central to the citation graph.
+	AuthoritativeRanking RankingProfile = "authoritative"
+)
+
+// Boosted rankings are computed over at least DefaultBoostCandidates
+// candidates of the search, which always cover the requested page.
+const DefaultBoostCandidates = 100
+
+// SignalBoost boosts journals by a signal, saturated as value / (value +
+// Pivot) so that it lies in [0, 1) and is halfway at Pivot.
+type SignalBoost struct {
+	Signal string
+	Weight float64
+	Pivot  float64
+}
+
+// RankingSettings are the boosts of a ranking profile. The score of a journal
+// is its relevance, min-max normalized over the candidates, plus the weighted
+// recency and signals; see ScoreComponent.
+type RankingSettings struct {
+	RecencyWeight float64
+	// RecencyHalfLife is the age in years at which the recency of a
+	// publication has halved.
+	RecencyHalfLife float64
+	Signals         []SignalBoost
+}
+
+// RankingSettings returns the boosts of the profile.
+func (p RankingProfile) RankingSettings() RankingSettings {
+	switch p {
+	case RecentRanking:
+		return RankingSettings{RecencyWeight: 0.5, RecencyHalfLife: DefaultRecencyHalfLife}
+	case CitedRanking:
+		return RankingSettings{
+			RecencyHalfLife: DefaultRecencyHalfLife,
+			Signals:         []SignalBoost{{Signal: CitationCountSignal, Weight: 0.3, Pivot: CitationPivot}},
+		}
+	case ClinicalRanking:
+		return RankingSettings{
+			RecencyWeight:   0.4,
+			RecencyHalfLife: DefaultRecencyHalfLife,
+			Signals:         []SignalBoost{{Signal: CitationCountSignal, Weight: 0.15, Pivot: CitationPivot}},
+		}
+	case AuthoritativeRanking:
+		return RankingSettings{
+			RecencyHalfLife: DefaultRecencyHalfLife,
+			Signals:         []SignalBoost{{Signal: AuthoritySignal, Weight: 0.3, Pivot: AuthorityPivot}},
+		}
+	}
+	return RankingSettings{RecencyHalfLife: DefaultRecencyHalfLife}
+}
+
+// IsBoosted reports whether the settings change the relevance ranking.
+func (s RankingSettings) IsBoosted() bool {
+	if s.RecencyWeight > 0 {
+		return true
+	}
+	for _, boost := range s.Signals {
+		if boost.Weight > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// Recency returns the recency of a publication date in (0, 1], halving every
+// RecencyHalfLife years of age at now. Unknown dates have no recency.
+func (s RankingSettings) Recency(publicationDate string, now time.Time) (recency, age float64) {
+	published, err := time.Parse(time.DateOnly, publicationDate)
+	if err != nil {
+		return 0, 0
+	}
+	age = max(now.Sub(published).Hours()/24/365.25, 0)
+	return math.Exp2(-age / s.RecencyHalfLife), age
+}
+
+// Score components, see ScoreComponent.
+const (
+	RelevanceComponent = "relevance"
+	RecencyComponent   = "recency"
+)
+
+// ScoreComponent is a term of the score of a boosted ranking, which is the
+// sum of the Weight * Value of its components. Value is Raw normalized into
+// [0, 1]: the min-max normalized relevance, the recency decay of the age in
+// years, or the saturated signal.
+type ScoreComponent struct {
+	Name   string  `json:"name"` // relevance, recency or the signal
+	Raw    float64 `json:"raw"`
+	Value  float64 `json:"value"`
+	Weight float64 `json:"weight"`
+}
+
+// RankingSettings returns the boosts of the ranking profile of the filter,
+// with the weights and half-life it overrides.
+func (f *JournalFilter) RankingSettings() RankingSettings {
+	if f == nil {
+		return RelevanceRanking.RankingSettings()
+	}
+
+	settings := f.Ranking.RankingSettings()
+	if f.RecencyWeight != nil {
+		settings.RecencyWeight = *f.RecencyWeight
+	}
+	if f.RecencyHalfLife != nil {
+		settings.RecencyHalfLife = *f.RecencyHalfLife
+	}
+	if f.CitationWeight != nil {
+		settings.setSignalWeight(CitationCountSignal, *f.CitationWeight, CitationPivot)
+	}
+	if f.AuthorityWeight != nil {
+		settings.setSignalWeight(AuthoritySignal, *f.AuthorityWeight, AuthorityPivot)
+	}
+	return settings
+}
+
+// setSignalWeight sets the weight of the boost by signal, adding the boost
+// with pivot when the settings have none.
+func (s *RankingSettings) setSignalWeight(signal string, weight, pivot float64) {
+	for i := range s.Signals {
+		if s.Signals[i].Signal == signal {
+			s.Signals[i].Weight = weight
+			return
+		}
+	}
+	s.Signals = append(s.Signals, SignalBoost{Signal: signal, Weight: weight, Pivot: pivot})
+}
+
+// IsBoosted reports whether the results of the filter are ranked with boosts.
+func (f *JournalFilter) IsBoosted() bool {
+	return f != nil && f.RankingSettings().IsBoosted()
+}
+
+func (f *JournalFilter) validateRanking() error {
+	switch f.Ranking {
+	case "", RelevanceRanking, RecentRanking, CitedRanking, ClinicalRanking, AuthoritativeRanking:
+	default:
+		return fmt.Errorf("%w: unknown ranking profile %q", ErrBadParamInput, f.Ranking)
+	}
+	if f.RecencyWeight != nil && *f.RecencyWeight < 0 {
+		return fmt.Errorf("%w: recency_weight must not be negative", ErrBadParamInput)
+	}
+	if f.RecencyHalfLife != nil && *f.RecencyHalfLife <= 0 {
+		return fmt.Errorf("%w: recency_half_life must be positive", ErrBadParamInput)
+	}
+	if f.CitationWeight != nil && *f.CitationWeight < 0 {
+		return fmt.Errorf("%w: citation_weight must not be negative", ErrBadParamInput)
+	}
+	if f.AuthorityWeight != nil && *f.AuthorityWeight < 0 {
+		return fmt.Errorf("%w: authority_weight must not be negative", ErrBadParamInput)
+	}
+	if !f.IsBoosted() {
+		return nil
+	}
+	if !f.IsRanked() {
+		return fmt.Errorf("%w: ranking boosts require search or v_search", ErrBadParamInput)
+	}
+	if f.Cursor != "" {
+		return fmt.Errorf("%w: ranking boosts do not support cursor, use page", ErrBadParamInput)
+	}
+	if f.MMR {
+		return fmt.Errorf("%w: ranking boosts cannot be combined with mmr", ErrBadParamInput)
+	}
+
+	return nil
+}
@@ -0,0 +1,18 @@
+package domain_test
+
+import (
+	"go-app/domain"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJournalFilter_RankingSettings(t *testing.T) {
+	t.Run("Overrides the weights of the profile", func(t *testing.T) {
+		zero := 0.0
+		filter := &domain.JournalFilter{Search: "hypertension", Ranking: domain.RecentRanking, RecencyWeight: &zero}
+
+		assert.False(t, filter.IsBoosted())
+		assert.Equal(t, domain.LexicalRankScore, filter.ScoreType())
+	})
+}
@@ -12,9 +12,34 @@ type ResponseSingleData[Data any] struct {
 }
 
 type ResponseMultipleData[Data any] struct {
-	Code    int    `json:"code"`    // number
-	Data    []Data `json:"data"`    // list of data
-	Message string `json:"message"` // string
+	Code    int       `json:"code"`           // number
+	Data    []Data    `json:"data"`           // list of data
+	Message string    `json:"message"`        // string
+	Meta    *ListMeta `json:"meta,omitempty"` // pagination of data
+	// Errors locates the invalid parts of a bad request, when known.
+	Errors []ErrorDetail `json:"errors,omitempty"`
+}
+
+// ErrorDetail locates what is invalid in the value of a request parameter.
+type ErrorDetail struct {
+	Param    string `json:"param"`           // name of the parameter
+	Position int    `json:"position"`        // offset in characters in its value
+	Token    string `json:"token,omitempty"` // offending part of the value
+	Message  string `json:"message"`
+}
+
+// ListMeta describes the page of a list response.
+type ListMeta struct {
+	Page           *int   `json:"page,omitempty"`            // nil when paginating by cursor
+	Limit          int    `json:"limit"`                     // requested page size
+	HasNext        bool   `json:"has_next"`                  // whether another page exists
+	NextCursor     string `json:"next_cursor,omitempty"`     // opaque token of the next page
+	Total          *int64 `json:"total,omitempty"`           // only when counting was requested
+	TotalEstimated bool   `json:"total_estimated,omitempty"` // total is a planner estimate
+	TookMs         int64  `json:"took_ms"`                   // server side processing time
+	// Suggestions are "did you mean" search queries, given when a search
+	// matched nothing.
+	Suggestions []string `json:"suggestions,omitempty"`
 }
 
 type Empty struct{}
@@ -0,0 +1,370 @@
+package domain
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// MaxSearchTerms bounds the number of terms of a boolean search query, and so
+// the size of the statement it compiles to.
+const MaxSearchTerms = 100
+
+// MaxSearchSuggestions bounds the "did you mean" queries returned when a
+// search matches nothing.
+const MaxSearchSuggestions = 5
+
+// SearchField is the field a term of a boolean search query is restricted
+// to, written as a prefix such as title:cancer.
+type SearchField string
+
+const (
+	AnySearchField      SearchField = ""         // title, abstract and content
+	TitleSearchField    SearchField = "title"    // words of the title
+	AbstractSearchField SearchField = "abstract" // words of the abstract
+	MeSHSearchField     SearchField = "mesh"     // exact MeSH heading
+	PMIDSearchField     SearchField = "pmid"     // PubMed identifier
+)
+
+// IsText reports whether terms of the field are matched as words of the
+// journal text, as opposed to exact values.
+func (f SearchField) IsText() bool {
+	return f == AnySearchField || f == TitleSearchField || f == AbstractSearchField
+}
+
+// SearchOperator combines the operands of a boolean search query node.
+type SearchOperator string
+
+const (
+	AndSearchOperator SearchOperator = "AND"
+	OrSearchOperator  SearchOperator = "OR"
+	NotSearchOperator SearchOperator = "NOT"
+)
+
+// SearchNode is a node of a parsed boolean search query. Operator nodes
+// combine their Operands (NOT has exactly one); the other nodes are terms,
+// a word or a quoted phrase restricted to Field.
+type SearchNode struct {
+	Operator SearchOperator
+	Operands []*SearchNode
+	Field    SearchField
+	Text     string
+	Phrase   bool
+	PMID     int64 // parsed Text of pmid terms
+	Position int   // offset in characters of the term in the query
+}
+
+// IsTerm reports whether the node is a term rather than an operator.
+func (n *SearchNode) IsTerm() bool {
+	return n.Operator == ""
+}
+
+// Keywords returns the words and phrases of the text terms that are not
+// negated, separated by spaces, e.g. to be compared against by a model.
+func (n *SearchNode) Keywords() string {
+	var keywords []string
+	var walk func(node *SearchNode, negated bool)
+	walk = func(node *SearchNode, negated bool) {
+		if node.IsTerm() {
+			if !negated && node.Field.IsText() {
+				keywords = append(keywords, node.Text)
+			}
+			return
+		}
+		for _, operand := range node.Operands {
+			walk(operand, negated != (node.Operator == NotSearchOperator))
+		}
+	}
+	walk(n, false)
+
+	return strings.Join(keywords, " ")
+}
+
+// SearchSyntaxError reports where a boolean search query is malformed.
+type SearchSyntaxError struct {
+	Position int    // offset in characters of the offending token
+	Token    string // offending token, empty at the end of the query
+	Message  string
+}
+
+func (e *SearchSyntaxError) Error() string {
+	return fmt.Sprintf("%s: invalid search query: %s at position %d", ErrBadParamInput, e.Message, e.Position)
+}
+
+// Unwrap makes syntax errors match ErrBadParamInput.
+func (e *SearchSyntaxError) Unwrap() error {
+	return ErrBadParamInput
+}
+
+type searchTokenKind int
+
+const (
+	searchEOF searchTokenKind = iota
+	searchWord
+	searchPhrase
+	searchField
+	searchOpen
+	searchClose
+	searchAnd
+	searchOr
+	searchNot
+)
+
+type searchToken struct {
+	kind     searchTokenKind
+	text     string
+	position int
+}
+
+// ParseSearchQuery parses a PubMed style boolean search query:
+//
+//	heart attack                 both words, anywhere in the journal
+//	"heart attack"               the words as a phrase
+//	aspirin OR ibuprofen         either word
+//	stroke NOT mesh:Mice         NOT excludes, as does a leading -
+//	title:(cancer OR tumor)      prefixes restrict terms or groups to a field
+//	pmid:12345                   fields are title, abstract, mesh and pmid
+//
+// Operators are uppercase. Like PubMed, AND, OR, NOT and juxtaposition
+// (implicit AND) are evaluated left to right; parentheses group. Malformed
+// queries return a *SearchSyntaxError.
+func ParseSearchQuery(query string) (*SearchNode, error) {
+	tokens, err := tokenizeSearchQuery(query)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &searchParser{tokens: tokens}
+	if p.peek().kind == searchEOF {
+		return nil, &SearchSyntaxError{Message: "query is empty"}
+	}
+	root, err := p.parseExpression(AnySearchField)
+	if err != nil {
+		return nil, err
+	}
+	if token := p.peek(); token.kind == searchClose {
+		return nil, &SearchSyntaxError{
+			Position: token.position, Token: token.text, Message: "unmatched closing parenthesis",
+		}
+	}
+
+	return root, nil
+}
+
+// EscapeSearchQuery turns free text into a search query of its words, all
+// required, by dropping the query syntax it may contain.
+func EscapeSearchQuery(text string) string {
+	words := strings.FieldsFunc(text, func(r rune) bool {
+		return unicode.IsSpace(r) || strings.ContainsRune(`()"`, r)
+	})
+
+	escaped := words[:0]
+	for _, word := range words {
+		word = strings.TrimLeft(word, "-")
+		for prefix, rest, ok := strings.Cut(word, ":"); ok && isSearchField(prefix); {
+			word = rest
+			prefix, rest, ok = strings.Cut(word, ":")
+		}
+		switch word {
+		case "":
+			continue
+		case "AND", "OR", "NOT":
+			word = strings.ToLower(word)
+		}
+		escaped = append(escaped, word)
+	}
+
+	return strings.Join(escaped, " ")
+}
+
+func tokenizeSearchQuery(query string) ([]searchToken, error) {
+	runes := []rune(query)
+	var tokens []searchToken
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			tokens = append(tokens, searchToken{kind: searchOpen, text: "(", position: i})
+			i++
+		case r == ')':
+			tokens = append(tokens, searchToken{kind: searchClose, text: ")", position: i})
+			i++
+		case r == '-':
+			tokens = append(tokens, searchToken{kind: searchNot, text: "-", position: i})
+			i++
+		case r == '"':
+			end := i + 1
+			for end < len(runes) && runes[end] != '"' {
+				end++
+			}
+			if end == len(runes) {
+				return nil, &SearchSyntaxError{Position: i, Token: `"`, Message: "unterminated phrase"}
+			}
+			phrase := strings.TrimSpace(string(runes[i+1 : end]))
+			if phrase == "" {
+				return nil, &SearchSyntaxError{Position: i, Token: `""`, Message: "empty phrase"}
+			}
+			tokens = append(tokens, searchToken{kind: searchPhrase, text: phrase, position: i})
+			i = end + 1
+		default:
+			end := i
+			for end < len(runes) && !unicode.IsSpace(runes[end]) && !strings.ContainsRune(`()"`, runes[end]) {
+				end++
+			}
+			word := string(runes[i:end])
+			if prefix, _, ok := strings.Cut(word, ":"); ok && isSearchField(prefix) {
+				// The field applies to whatever follows the colon, so that
+				// title:"..." and title:(...) are tokenized like title:word.
+				tokens = append(tokens, searchToken{kind: searchField, text: prefix, position: i})
+				i += len([]rune(prefix)) + 1
+				continue
+			}
+
+			kind := searchWord
+			switch word {
+			case "AND":
+				kind = searchAnd
+			case "OR":
+				kind = searchOr
+			case "NOT":
+				kind = searchNot
+			}
+			tokens = append(tokens, searchToken{kind: kind, text: word, position: i})
+			i = end
+		}
+	}
+
+	return append(tokens, searchToken{kind: searchEOF, position: len(runes)}), nil
+}
+
+func isSearchField(prefix string) bool {
+	switch SearchField(strings.ToLower(prefix)) {
+	case TitleSearchField, AbstractSearchField, MeSHSearchField, PMIDSearchField:
+		return true
+	}
+	return false
+}
+
+type searchParser struct {
+	tokens []searchToken
+	next   int
+	terms  int
+}
+
+func (p *searchParser) peek() searchToken {
+	return p.tokens[p.next]
+}
+
+func (p *searchParser) advance() searchToken {
+	token := p.tokens[p.next]
+	if token.kind != searchEOF {
+		p.next++
+	}
+	return token
+}
+
+// parseExpression parses operands joined by operators up to a closing
+// parenthesis or the end of the query, folding them from the left. Terms
+// without a prefix of their own are restricted to field.
+func (p *searchParser) parseExpression(field SearchField) (*SearchNode, error) {
+	left, err := p.parseOperand(field, "")
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		operator, after := AndSearchOperator, ""
+		switch token := p.peek(); token.kind {
+		case searchEOF, searchClose:
+			return left, nil
+		case searchAnd, searchOr:
+			// A NOT is parsed with its operand, "a NOT b" being a AND NOT b.
+			p.advance()
+			operator, after = SearchOperator(token.text), token.text
+		}
+
+		right, err := p.parseOperand(field, after)
+		if err != nil {
+			return nil, err
+		}
+		left = combineSearchNodes(operator, left, right)
+	}
+}
+
+// parseOperand parses a possibly negated term or group. after is the
+// operator preceding the operand, for error messages.
+func (p *searchParser) parseOperand(field SearchField, after string) (*SearchNode, error) {
+	token := p.advance()
+	switch token.kind {
+	case searchNot:
+		operand, err := p.parseOperand(field, token.text)
+		if err != nil {
+			return nil, err
+		}
+		return &SearchNode{Operator: NotSearchOperator, Operands: []*SearchNode{operand}}, nil
+	case searchField:
+		return p.parseOperand(SearchField(strings.ToLower(token.text)), token.text+":")
+	case searchOpen:
+		if p.peek().kind == searchClose {
+			return nil, &SearchSyntaxError{Position: token.position, Token: "()", Message: "empty parentheses"}
+		}
+		group, err := p.parseExpression(field)
+		if err != nil {
+			return nil, err
+		}
+		if p.peek().kind != searchClose {
+			return nil, &SearchSyntaxError{
+				Position: token.position, Token: token.text, Message: "missing closing parenthesis",
+			}
+		}
+		p.advance()
+		return group, nil
+	case searchWord, searchPhrase:
+		return p.term(token, field)
+	}
+
+	message := "expected a term"
+	if after != "" {
+		message = fmt.Sprintf("expected a term after %s", after)
+	}
+	if token.kind == searchAnd || token.kind == searchOr {
+		message = fmt.Sprintf("%s is missing its left operand", token.text)
+	}
+	return nil, &SearchSyntaxError{Position: token.position, Token: token.text, Message: message}
+}
+
+func (p *searchParser) term(token searchToken, field SearchField) (*SearchNode, error) {
+	p.terms++
+	if p.terms > MaxSearchTerms {
+		return nil, &SearchSyntaxError{
+			Position: token.position, Token: token.text,
+			Message: fmt.Sprintf("query has more than %d terms", MaxSearchTerms),
+		}
+	}
+
+	node := &SearchNode{Field: field, Text: token.text, Phrase: token.kind == searchPhrase, Position: token.position}
+	if field == PMIDSearchField {
+		pmid, err := strconv.ParseInt(token.text, 10, 64)
+		if err != nil || pmid < 1 {
+			return nil, &SearchSyntaxError{
+				Position: token.position, Token: token.text, Message: "pmid must be a positive integer",
+			}
+		}
+		node.PMID = pmid
+	}
+
+	return node, nil
+}
+
+// combineSearchNodes joins left and right with operator, flattening chains
+// of the same operator.
+func combineSearchNodes(operator SearchOperator, left, right *SearchNode) *SearchNode {
+	if left.Operator == operator {
+		left.Operands = append(left.Operands, right)
+		return left
+	}
+	return &SearchNode{Operator: operator, Operands: []*SearchNode{left, right}}
+}
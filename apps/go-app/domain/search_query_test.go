@@ -0,0 +1,68 @@
+package domain_test
+
+import (
+	"go-app/domain"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseSearchQuery(t *testing.T) {
+	t.Run("Parses operators left to right", func(t *testing.T) {
+		query, err := domain.ParseSearchQuery(`aspirin OR "heart attack" title:stroke NOT mesh:Mice`)
+
+		assert.NoError(t, err)
+		assert.Equal(t, domain.AndSearchOperator, query.Operator)
+		assert.Len(t, query.Operands, 3)
+		assert.Equal(t, domain.OrSearchOperator, query.Operands[0].Operator)
+		assert.True(t, query.Operands[0].Operands[1].Phrase)
+		assert.Equal(t, domain.TitleSearchField, query.Operands[1].Field)
+		assert.Equal(t, domain.NotSearchOperator, query.Operands[2].Operator)
+		assert.Equal(t, "Mice", query.Operands[2].Operands[0].Text)
+		assert.Equal(t, 48, query.Operands[2].Operands[0].Position)
+		assert.Equal(t, "aspirin heart attack stroke", query.Keywords())
+	})
+
+	t.Run("Restricts groups to their field", func(t *testing.T) {
+		query, err := domain.ParseSearchQuery(`pmid:(12 OR 34) -abstract:(rats mesh:Rats)`)
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(34), query.Operands[0].Operands[1].PMID)
+		group := query.Operands[1].Operands[0]
+		assert.Equal(t, domain.AbstractSearchField, group.Operands[0].Field)
+		assert.Equal(t, domain.MeSHSearchField, group.Operands[1].Field)
+	})
+
+	t.Run("Reports where the syntax is invalid", func(t *testing.T) {
+		cases := map[string]domain.SearchSyntaxError{
+			`sepsis AND`:           {Position: 10, Message: "expected a term after AND"},
+			`(sepsis OR shock`:     {Position: 0, Token: "(", Message: "missing closing parenthesis"},
+			`sepsis) shock`:        {Position: 6, Token: ")", Message: "unmatched closing parenthesis"},
+			`OR sepsis`:            {Position: 0, Token: "OR", Message: "OR is missing its left operand"},
+			`title:"septic shock`:  {Position: 6, Token: `"`, Message: "unterminated phrase"},
+			`pmid:abc`:             {Position: 5, Token: "abc", Message: "pmid must be a positive integer"},
+			`sepsis () shock`:      {Position: 7, Token: "()", Message: "empty parentheses"},
+			`   `:                  {Position: 0, Message: "query is empty"},
+			`sepsis NOT (shock OR`: {Position: 20, Message: "expected a term after OR"},
+		}
+		for query, want := range cases {
+			_, err := domain.ParseSearchQuery(query)
+
+			var syntaxErr *domain.SearchSyntaxError
+			if assert.ErrorAs(t, err, &syntaxErr, query) {
+				assert.Equal(t, want, *syntaxErr, query)
+			}
+			assert.ErrorIs(t, err, domain.ErrBadParamInput, query)
+		}
+	})
+}
+
+func TestEscapeSearchQuery(t *testing.T) {
+	t.Run("Escapes free text", func(t *testing.T) {
+		escaped := domain.EscapeSearchQuery(`"Aspirin" (ASA) AND -stroke title: NOT`)
+
+		assert.Equal(t, "Aspirin ASA and stroke not", escaped)
+		_, err := domain.ParseSearchQuery(escaped)
+		assert.NoError(t, err)
+	})
+}
@@ -0,0 +1,54 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// Typeahead bounds. Prefixes shorter than MinSuggestPrefix match too much to
+// be useful and longer than MaxSuggestPrefix are no longer being typed.
+const (
+	MinSuggestPrefix    = 2
+	MaxSuggestPrefix    = 100
+	DefaultSuggestLimit = 8
+	MaxSuggestLimit     = 20
+)
+
+// SuggestionSource is where a completion comes from.
+type SuggestionSource string
+
+const (
+	TitleSuggestion SuggestionSource = "title" // a journal title, with its PMID
+	MeSHSuggestion  SuggestionSource = "mesh"  // a MeSH heading of the journals
+)
+
+// Suggestion is a completion of a typeahead prefix.
+type Suggestion struct {
+	Text   string           `json:"text"`
+	Source SuggestionSource `json:"source"`
+	PMID   *int64           `json:"pmid,omitempty"` // journal of a title completion
+	// Score ranks the completions, higher first: the trigram similarity of
+	// the completion to the prefix, so that the closest completions come
+	// first.
+	Score float64 `json:"score"`
+}
+
+type SuggestFilter struct {
+	Prefix string `json:"prefix" query:"prefix"`
+	Limit  *int   `json:"limit" query:"limit"`
+}
+
+func (f *SuggestFilter) Validate() error {
+	prefix := strings.TrimSpace(f.Prefix)
+	if n := utf8.RuneCountInString(prefix); n < MinSuggestPrefix || n > MaxSuggestPrefix {
+		return fmt.Errorf(
+			"%w: prefix must be between %d and %d characters", ErrBadParamInput, MinSuggestPrefix, MaxSuggestPrefix,
+		)
+	}
+	if f.Limit != nil && (*f.Limit < 1 || *f.Limit > MaxSuggestLimit) {
+		return fmt.Errorf("%w: limit must be between 1 and %d", ErrBadParamInput, MaxSuggestLimit)
+	}
+
+	return nil
+}
@@ -102,3 +102,60 @@ func (r *EmbeddingHTTPRepository) GetGeneralEmbedding(
 
 	return &response.Data, nil
 }
+
+// GetRerankScores scores every document against the query with the
+// cross-encoder of the AI service. Scores are returned in document order.
+func (r *EmbeddingHTTPRepository) GetRerankScores(
+	ctx context.Context,
+	query string,
+	documents []string,
+) ([]float64, error) {
+	url := fmt.Sprintf("%s/rerank", r.aiURL)
+
+	data := domain.RerankInput{
+		Query:     query,
+		Documents: documents,
+	}
+
+	reqBody, err := json.Marshal(data)
+	if err != nil {
+		logging.LogError(ctx, err, "Error marshaling json")
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewBuffer(reqBody))
+	if err != nil {
+		logging.LogError(ctx, err, "Failed to create request")
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	resp, err := r.c.Do(req)
+	if err != nil {
+		logging.LogError(ctx, err, "Failed to send request")
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		bodyBytes, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return nil, fmt.Errorf("Error reading http response: %v", err)
+		}
+
+		return nil, fmt.Errorf("failed to rerank documents, status code: %d: %s", resp.StatusCode, bodyBytes)
+	}
+
+	var response domain.RerankOutput
+	err = json.NewDecoder(resp.Body).Decode(&response)
+	if err != nil {
+		logging.LogError(ctx, err, "Failed to decode response body")
+		return nil, err
+	}
+	if len(response.Data) != len(documents) {
+		return nil, fmt.Errorf("rerank returned %d scores for %d documents", len(response.Data), len(documents))
+	}
+
+	return response.Data, nil
+}
@@ -2,11 +2,13 @@ package postgres
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"go-app/domain"
+	"html"
 	"strings"
 
-	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/pgvector/pgvector-go"
@@ -27,88 +29,399 @@ func NewJournalRepository(conn *pgxpool.Pool) *JournalRepository {
 func (u *JournalRepository) GetJournalList(
 	ctx context.Context,
 	filter *domain.JournalFilter,
-	embedding *pgvector.Vector,
+	embeddings domain.QueryEmbeddings,
 ) ([]domain.JournalResponse, error) {
-	query := `
-		SELECT
-            pmid,
-            title,
-            abstract,
-            content,
-            mesh_terms,
-            0 as distance
-		FROM journals`
-
-	if filter != nil && filter.VSearch != "" && embedding != nil {
-		embeddingTable := ""
-		switch filter.Type {
-		case domain.GeneralVectorType:
-			embeddingTable = "journal_generalist_embeddings"
-		case domain.SpecialistVectorType:
-			embeddingTable = "journal_specialist_embeddings"
+	tracer := otel.Tracer("repo.journal")
+	ctx, span := tracer.Start(ctx, "JournalRepository.GetJournalList")
+	defer span.End()
+
+	query, args, err := journalListQuery(filter, embeddings, true)
+	if err != nil {
+		return nil, err
+	}
+
+	span.SetAttributes(attribute.String("query.statement", query))
+	span.SetAttributes(attribute.String("query.recall", string(filter.RecallProfile())))
+	journals, err := collectSearch(
+		ctx, u.Conn, filter.RecallProfile(), pgx.RowToStructByName[domain.JournalResponse], query, args,
+	)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+	if len(journals) == 0 && filter != nil && filter.Passages {
+		// Tell a search without matches from one over journals never chunked.
+		var chunked bool
+		if err := u.Conn.QueryRow(ctx, "SELECT EXISTS (SELECT 1 FROM journal_chunks)").Scan(&chunked); err != nil {
+			span.RecordError(err)
+			return nil, err
 		}
-		query = fmt.Sprintf(`
-            SELECT
-                j.pmid,
-                title,
-                abstract,
-                content,
-                mesh_terms,
-                1 - (je.embeddings <=> @query) as distance
-            FROM journals j
-            INNER JOIN %s je ON j.pmid = je.pmid
-        `, embeddingTable)
+		if !chunked {
+			span.RecordError(domain.ErrPassagesNotIndexed)
+			return nil, domain.ErrPassagesNotIndexed
+		}
+	}
+
+	return journals, nil
+}
+
+// GetJournalCandidates returns the page of journals matched by filter together
+// with their stored embeddings of filter.Type, best first. Ensemble searches
+// return the embeddings of the first of their models.
+func (u *JournalRepository) GetJournalCandidates(
+	ctx context.Context,
+	filter *domain.JournalFilter,
+	embeddings domain.QueryEmbeddings,
+) ([]domain.JournalCandidate, error) {
+	tracer := otel.Tracer("repo.journal")
+	ctx, span := tracer.Start(ctx, "JournalRepository.GetJournalCandidates")
+	defer span.End()
+
+	query, args, err := journalListQuery(filter, embeddings, true)
+	if err != nil {
+		return nil, err
+	}
+
+	query = fmt.Sprintf(`
+        SELECT
+            c.*,
+            e.embeddings as embedding
+        FROM (%s) c
+        INNER JOIN %s e ON e.pmid = c.pmid
+        ORDER BY c.distance DESC, c.pmid`, query, embeddingTable(filter.Type.Models()[0]))
+
+	span.SetAttributes(attribute.String("query.statement", query))
+	span.SetAttributes(attribute.String("query.recall", string(filter.RecallProfile())))
+	candidates, err := collectSearch(
+		ctx, u.Conn, filter.RecallProfile(), pgx.RowToStructByName[domain.JournalCandidate], query, args,
+	)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	return candidates, nil
+}
+
+// CountJournals counts every journal matched by filter, ignoring pagination.
+// Unless exact is set the count is the planner's row estimate, which avoids
+// ranking the whole embedding table on vector queries.
+func (u *JournalRepository) CountJournals(
+	ctx context.Context,
+	filter *domain.JournalFilter,
+	embeddings domain.QueryEmbeddings,
+	exact bool,
+) (int64, error) {
+	tracer := otel.Tracer("repo.journal")
+	ctx, span := tracer.Start(ctx, "JournalRepository.CountJournals")
+	defer span.End()
+
+	query, args, err := journalListQuery(filter, embeddings, false)
+	if err != nil {
+		return 0, err
+	}
+
+	span.SetAttributes(attribute.Bool("query.exact", exact))
+	if exact {
+		var total int64
+		err = u.Conn.QueryRow(ctx, "SELECT count(*) FROM ("+query+") matches", args).Scan(&total)
+		if err != nil {
+			span.RecordError(err)
+			return 0, err
+		}
+		return total, nil
+	}
+
+	// EXPLAIN returns its JSON document as text.
+	var rawPlan string
+	err = u.Conn.QueryRow(ctx, "EXPLAIN (FORMAT JSON) "+query, args).Scan(&rawPlan)
+	if err != nil {
+		span.RecordError(err)
+		return 0, err
+	}
+
+	var plan []struct {
+		Plan struct {
+			Rows float64 `json:"Plan Rows"`
+		} `json:"Plan"`
+	}
+	if err := json.Unmarshal([]byte(rawPlan), &plan); err != nil {
+		span.RecordError(err)
+		return 0, err
+	}
+	if len(plan) == 0 {
+		return 0, nil
+	}
+
+	return int64(plan[0].Plan.Rows), nil
+}
+
+// GetMeSHFacets counts the MeSH terms of the journals matched by filter and
+// returns the size most frequent ones. Vector queries are ranked over the whole
+// embedding table, so only their depth best candidates are counted.
+func (u *JournalRepository) GetMeSHFacets(
+	ctx context.Context,
+	filter *domain.JournalFilter,
+	embeddings domain.QueryEmbeddings,
+	size int,
+	depth int,
+) ([]domain.FacetCount, error) {
+	tracer := otel.Tracer("repo.journal")
+	ctx, span := tracer.Start(ctx, "JournalRepository.GetMeSHFacets")
+	defer span.End()
+
+	// Only the MeSH terms of the matches are read.
+	matches := *filter
+	matches.Fields, matches.OmitContent = "mesh_terms", false
+
+	var (
+		query string
+		args  pgx.StrictNamedArgs
+		err   error
+	)
+	if filter.VSearch != "" && embeddings != nil {
+		page := 0
+		matches.Limit, matches.Page, matches.Cursor = &depth, &page, ""
+		query, args, err = journalListQuery(&matches, embeddings, true)
+	} else {
+		query, args, err = journalListQuery(&matches, embeddings, false)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	query = `
+        SELECT
+            term as value,
+            count(*) as count
+        FROM (` + query + `) matches, unnest(matches.mesh_terms) term
+        GROUP BY term
+        ORDER BY count DESC, term
+        LIMIT @facet_size`
+	args["facet_size"] = size
+
+	span.SetAttributes(attribute.String("query.statement", query))
+	facets, err := collectSearch(
+		ctx, u.Conn, filter.RecallProfile(), pgx.RowToStructByName[domain.FacetCount], query, args,
+	)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
 	}
 
-	args := pgx.StrictNamedArgs{}
-	var conditions []string
-	if filter != nil && filter.Search != "" {
-		conditions = append(conditions, `(title ILIKE @title OR abstract ILIKE @title OR content ILIKE @title)`)
-		args["title"] = "%" + filter.Search + "%"
+	return facets, nil
+}
+
+// SampleEmbeddings returns the stored embeddings of up to n random journals,
+// for use as realistic query vectors when evaluating the index.
+func (u *JournalRepository) SampleEmbeddings(
+	ctx context.Context,
+	vType domain.VectorType,
+	n int,
+) ([]pgvector.Vector, error) {
+	query := fmt.Sprintf("SELECT embeddings FROM %s ORDER BY random() LIMIT $1", embeddingTable(vType))
+	rows, err := u.Conn.Query(ctx, query, n)
+	if err != nil {
+		return nil, err
 	}
 
-	if len(conditions) > 0 {
-		query += fmt.Sprintf(" WHERE %s", strings.Join(conditions, " AND "))
+	return pgx.CollectRows(rows, pgx.RowTo[pgvector.Vector])
+}
+
+// GetSimilarJournals returns the nearest neighbours of the journal's stored
+// embedding, excluding the journal itself. It returns domain.ErrNotFound when
+// the journal has no embedding of the requested type.
+func (u *JournalRepository) GetSimilarJournals(
+	ctx context.Context,
+	pmid int64,
+	filter *domain.SimilarJournalFilter,
+) ([]domain.JournalResponse, error) {
+	tracer := otel.Tracer("repo.journal")
+	ctx, span := tracer.Start(ctx, "JournalRepository.GetSimilarJournals")
+	defer span.End()
+
+	table := embeddingTable(filter.Type)
+	span.SetAttributes(attribute.Int64("query.pmid", pmid))
+	span.SetAttributes(attribute.String("query.table", table))
+
+	var embedding pgvector.Vector
+	err := u.Conn.QueryRow(ctx, fmt.Sprintf("SELECT embeddings FROM %s WHERE pmid = $1", table), pmid).
+		Scan(&embedding)
+	if err != nil {
+		span.RecordError(err)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, err
 	}
 
-	if filter != nil && filter.VSearch != "" && embedding != nil {
-		query += " ORDER BY je.embeddings <-> @query "
-		args["query"] = embedding
+	limit := 10
+	if filter.Limit != nil {
+		limit = *filter.Limit
 	}
 
-	if filter.Limit != nil && filter.Page != nil {
-		query += " LIMIT @limit OFFSET @offset"
-		args["limit"] = *filter.Limit
-		args["offset"] = *filter.Page * *filter.Limit
+	args := pgx.StrictNamedArgs{
+		"query": embedding,
+		"pmid":  pmid,
+		"limit": limit,
+	}
+	minScore := ""
+	if filter.MinScore != nil {
+		minScore = fmt.Sprintf("AND %s >= @min_score", vectorScore(filter.Type.Metric(), "je.embeddings", "@query"))
+		args["min_score"] = *filter.MinScore
 	}
+
+	query := fmt.Sprintf(`
+        SELECT
+            j.pmid,
+            %s,
+            %s as distance,
+            0::float8 as lexical_score,
+            NULL::jsonb as passage
+        FROM %s je
+        INNER JOIN journals j ON j.pmid = je.pmid
+        WHERE je.pmid <> @pmid %s
+        ORDER BY %s
+        LIMIT @limit`,
+		journalColumnList(nil, "j."), vectorScore(filter.Type.Metric(), "je.embeddings", "@query"), table, minScore,
+		vectorOrder(filter.Type.Metric(), "je.embeddings", "@query"))
+
 	rows, err := u.Conn.Query(ctx, query, args)
 	if err != nil {
+		span.RecordError(err)
 		return nil, err
 	}
 	defer rows.Close()
 
 	journals, err := pgx.CollectRows(rows, pgx.RowToStructByName[domain.JournalResponse])
 	if err != nil {
+		span.RecordError(err)
 		return nil, err
 	}
 
 	return journals, nil
 }
 
-func (u *JournalRepository) GetJournal(ctx context.Context, id uuid.UUID) (*domain.Journal, error) {
+// Highlight markers. ts_headline does not escape the text it marks up, so the
+// matches are delimited by control characters that are turned into <mark>
+// tags once the rest of the text has been HTML escaped.
+const (
+	highlightStart     = "\x01"
+	highlightStop      = "\x02"
+	highlightDelimiter = "\x03"
+)
+
+var highlightMarkup = strings.NewReplacer(highlightStart, "<mark>", highlightStop, "</mark>")
+
+func markHighlight(snippet string) string {
+	return highlightMarkup.Replace(html.EscapeString(snippet))
+}
+
+// GetHighlights returns the snippets of the journals with the given PMIDs
+// that match the text terms of query, parsed like the lexical search, or as
+// free text when it is not a valid boolean query (e.g. a v_search). Only the
+// given journals are highlighted since ts_headline has to parse each document
+// again.
+func (u *JournalRepository) GetHighlights(
+	ctx context.Context,
+	query string,
+	pmids []int64,
+) ([]domain.JournalHighlights, error) {
+	tracer := otel.Tracer("repo.journal")
+	ctx, span := tracer.Start(ctx, "JournalRepository.GetHighlights")
+	defer span.End()
+
+	statement := `
+        SELECT
+            pmid,
+            CASE WHEN to_tsvector('english', title) @@ q
+                THEN ts_headline('english', title, q, @title_options)
+            END as title,
+            CASE WHEN to_tsvector('english', abstract) @@ q
+                THEN ts_headline('english', abstract, q, @abstract_options)
+            END as abstract,
+            CASE WHEN to_tsvector('english', content) @@ q
+                THEN string_to_array(ts_headline('english', content, q, @content_options), @delimiter)
+            END as content
+        FROM journals, %s q
+        WHERE pmid = ANY(@pmids)`
+	options := fmt.Sprintf(
+		`StartSel="%s", StopSel="%s", FragmentDelimiter="%s"`,
+		highlightStart, highlightStop, highlightDelimiter,
+	)
+	args := pgx.StrictNamedArgs{
+		"pmids":            pmids,
+		"delimiter":        highlightDelimiter,
+		"title_options":    options + ", HighlightAll=true",
+		"abstract_options": options + ", MaxFragments=1, MaxWords=50, MinWords=20",
+		"content_options": fmt.Sprintf(
+			"%s, MaxFragments=%d, MaxWords=35, MinWords=15", options, domain.MaxContentHighlights,
+		),
+	}
+
+	tsquery := "websearch_to_tsquery('english', @query)"
+	if search, err := compileSearch(query, "", args); err == nil {
+		if tsquery = search.rank(); tsquery == "" {
+			// Nothing to highlight in MeSH or PMID lookups.
+			return nil, nil
+		}
+	} else {
+		args["query"] = query
+	}
+	statement = fmt.Sprintf(statement, tsquery)
+
+	span.SetAttributes(attribute.String("query.statement", statement))
+	span.SetAttributes(attribute.Int("query.pmid_count", len(pmids)))
+	rows, err := u.Conn.Query(ctx, statement, args)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	highlights, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (domain.JournalHighlights, error) {
+		var (
+			h               domain.JournalHighlights
+			title, abstract *string
+		)
+		if err := row.Scan(&h.PMID, &title, &abstract, &h.Content); err != nil {
+			return h, err
+		}
+		if title != nil {
+			h.Title = markHighlight(*title)
+		}
+		if abstract != nil {
+			h.Abstract = markHighlight(*abstract)
+		}
+		for i, fragment := range h.Content {
+			h.Content[i] = markHighlight(fragment)
+		}
+		return h, nil
+	})
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	return highlights, nil
+}
+
+// GetJournal returns the journal with the given PMID, or domain.ErrNotFound.
+// Columns missing from fields (every field when nil) are left empty.
+func (u *JournalRepository) GetJournal(ctx context.Context, pmid int64, fields []string) (*domain.Journal, error) {
 	tracer := otel.Tracer("repo.journal")
 	ctx, span := tracer.Start(ctx, "JournalRepository.GetJournal")
 	defer span.End()
 
 	query := `
 		SELECT
-            *
+            pmid, ` + journalColumnList(fields, "") + `
 		FROM journals
-		WHERE id = $1`
+		WHERE pmid = $1`
 
 	span.SetAttributes(attribute.String("query.statement", query))
-	span.SetAttributes(attribute.String("query.parameter", id.String()))
-	rows, err := u.Conn.Query(ctx, query, id)
+	span.SetAttributes(attribute.Int64("query.parameter", pmid))
+	rows, err := u.Conn.Query(ctx, query, pmid)
 	if err != nil {
 		return nil, err
 	}
@@ -117,8 +430,43 @@ func (u *JournalRepository) GetJournal(ctx context.Context, id uuid.UUID) (*doma
 	journal, err := pgx.CollectExactlyOneRow(rows, pgx.RowToAddrOfStructByName[domain.Journal])
 	if err != nil {
 		span.RecordError(err)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
 		return nil, err
 	}
 
 	return journal, nil
 }
+
+// GetJournalsByPMIDs returns the journals matching any of the given PMIDs.
+// PMIDs that do not exist are silently skipped; rows come back in PMID order.
+func (u *JournalRepository) GetJournalsByPMIDs(ctx context.Context, pmids []int64) ([]domain.Journal, error) {
+	tracer := otel.Tracer("repo.journal")
+	ctx, span := tracer.Start(ctx, "JournalRepository.GetJournalsByPMIDs")
+	defer span.End()
+
+	query := `
+		SELECT
+            pmid, ` + journalColumnList(nil, "") + `
+		FROM journals
+		WHERE pmid = ANY($1)
+		ORDER BY pmid`
+
+	span.SetAttributes(attribute.String("query.statement", query))
+	span.SetAttributes(attribute.Int("query.pmid_count", len(pmids)))
+	rows, err := u.Conn.Query(ctx, query, pmids)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	journals, err := pgx.CollectRows(rows, pgx.RowToStructByName[domain.Journal])
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	return journals, nil
+}
@@ -0,0 +1,104 @@
+package postgres
+
+import (
+	"context"
+	"go-app/domain"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+type JournalChunkRepository struct {
+	Conn *pgxpool.Pool
+}
+
+func NewJournalChunkRepository(conn *pgxpool.Pool) *JournalChunkRepository {
+	return &JournalChunkRepository{
+		Conn: conn,
+	}
+}
+
+// GetUnchunkedJournals returns up to limit journals with content but no
+// chunks yet, in PMID order.
+func (u *JournalChunkRepository) GetUnchunkedJournals(ctx context.Context, limit int) ([]domain.Journal, error) {
+	tracer := otel.Tracer("repo.journal_chunk")
+	ctx, span := tracer.Start(ctx, "JournalChunkRepository.GetUnchunkedJournals")
+	defer span.End()
+
+	query := `
+		SELECT
+            j.pmid, ` + journalColumnList([]string{"content"}, "j.") + `
+		FROM journals j
+		WHERE content ~ '\S'
+            AND NOT EXISTS (SELECT 1 FROM journal_chunks c WHERE c.pmid = j.pmid)
+		ORDER BY pmid
+		LIMIT $1`
+
+	span.SetAttributes(attribute.String("query.statement", query))
+	rows, err := u.Conn.Query(ctx, query, limit)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	journals, err := pgx.CollectRows(rows, pgx.RowToStructByName[domain.Journal])
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	return journals, nil
+}
+
+// ReplaceJournalChunks replaces the chunks of the journal with pmid in one
+// transaction, so searches never see a partially chunked journal.
+func (u *JournalChunkRepository) ReplaceJournalChunks(
+	ctx context.Context,
+	pmid int64,
+	chunks []domain.JournalChunk,
+) error {
+	tracer := otel.Tracer("repo.journal_chunk")
+	ctx, span := tracer.Start(ctx, "JournalChunkRepository.ReplaceJournalChunks")
+	defer span.End()
+
+	span.SetAttributes(attribute.Int64("query.pmid", pmid))
+	span.SetAttributes(attribute.Int("query.chunk_count", len(chunks)))
+
+	tx, err := u.Conn.Begin(ctx)
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, "DELETE FROM journal_chunks WHERE pmid = $1", pmid); err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	rows := make([][]any, len(chunks))
+	for i, c := range chunks {
+		rows[i] = []any{
+			pmid, c.Index, c.Text, c.Start, c.End,
+			c.Embeddings[domain.GeneralVectorType], c.Embeddings[domain.SpecialistVectorType],
+		}
+	}
+	_, err = tx.CopyFrom(
+		ctx,
+		pgx.Identifier{"journal_chunks"},
+		[]string{
+			"pmid", "chunk_index", "content", "start_offset", "end_offset",
+			chunkEmbeddingColumn(domain.GeneralVectorType), chunkEmbeddingColumn(domain.SpecialistVectorType),
+		},
+		pgx.CopyFromRows(rows),
+	)
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
@@ -0,0 +1,235 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"go-app/domain"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+type JournalCitationRepository struct {
+	Conn *pgxpool.Pool
+}
+
+func NewJournalCitationRepository(conn *pgxpool.Pool) *JournalCitationRepository {
+	return &JournalCitationRepository{
+		Conn: conn,
+	}
+}
+
+// citationNeighbours returns the subquery of the PMIDs of the neighbours of
+// @pmid by relation, with their co_citations.
+func citationNeighbours(relation domain.CitationRelation) string {
+	switch relation {
+	case domain.CitedByRelation:
+		return `SELECT citing_pmid AS pmid, 0::bigint AS co_citations
+            FROM journal_citations
+            WHERE cited_pmid = @pmid`
+	case domain.CoCitedRelation:
+		return `SELECT other.cited_pmid AS pmid, count(*) AS co_citations
+            FROM journal_citations c
+            INNER JOIN journal_citations other
+                ON other.citing_pmid = c.citing_pmid AND other.cited_pmid <> c.cited_pmid
+            WHERE c.cited_pmid = @pmid
+            GROUP BY other.cited_pmid`
+	}
+	return `SELECT cited_pmid AS pmid, 0::bigint AS co_citations
+            FROM journal_citations
+            WHERE citing_pmid = @pmid`
+}
+
+// GetCitationNeighbours returns the neighbours of the journal with the given
+// PMID by relation, the most co-cited then the most authoritative first.
+func (u *JournalCitationRepository) GetCitationNeighbours(
+	ctx context.Context,
+	pmid int64,
+	relation domain.CitationRelation,
+	limit int,
+	offset int,
+) ([]domain.CitationNeighbour, error) {
+	tracer := otel.Tracer("repo.journal_citation")
+	ctx, span := tracer.Start(ctx, "JournalCitationRepository.GetCitationNeighbours")
+	defer span.End()
+
+	query := fmt.Sprintf(`
+        SELECT
+            n.pmid,
+            j.pmid IS NOT NULL AS in_corpus,
+            COALESCE(j.title, '') AS title,
+            COALESCE(j.journal_name, '') AS journal_name,
+            COALESCE(to_char(j.publication_date, 'YYYY-MM-DD'), '') AS publication_date,
+            COALESCE(s.value, 0)::float8 AS authority,
+            n.co_citations
+        FROM (%s) n
+        LEFT JOIN journals j ON j.pmid = n.pmid
+        LEFT JOIN journal_signals s ON s.pmid = n.pmid AND s.name = @authority
+        ORDER BY n.co_citations DESC, authority DESC, n.pmid
+        LIMIT @limit OFFSET @offset`, citationNeighbours(relation))
+	args := pgx.StrictNamedArgs{
+		"pmid":      pmid,
+		"authority": domain.AuthoritySignal,
+		"limit":     limit,
+		"offset":    offset,
+	}
+
+	span.SetAttributes(attribute.String("query.statement", query))
+	span.SetAttributes(attribute.Int64("query.pmid", pmid))
+	rows, err := u.Conn.Query(ctx, query, args)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	neighbours, err := pgx.CollectRows(rows, pgx.RowToStructByName[domain.CitationNeighbour])
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	return neighbours, nil
+}
+
+// HasCitationNode reports whether the PMID is a journal of the corpus or is
+// cited by one, and so a node of the citation graph.
+func (u *JournalCitationRepository) HasCitationNode(ctx context.Context, pmid int64) (bool, error) {
+	tracer := otel.Tracer("repo.journal_citation")
+	ctx, span := tracer.Start(ctx, "JournalCitationRepository.HasCitationNode")
+	defer span.End()
+
+	var exists bool
+	err := u.Conn.QueryRow(ctx, `
+        SELECT EXISTS (SELECT 1 FROM journals WHERE pmid = @pmid)
+            OR EXISTS (SELECT 1 FROM journal_citations WHERE cited_pmid = @pmid)`,
+		pgx.StrictNamedArgs{"pmid": pmid},
+	).Scan(&exists)
+	if err != nil {
+		span.RecordError(err)
+		return false, err
+	}
+
+	return exists, nil
+}
+
+// ImportCitations stores citations, skipping self-citations, the ones already
+// stored and the ones whose citing journal is not in the corpus, and returns
+// how many were stored.
+func (u *JournalCitationRepository) ImportCitations(
+	ctx context.Context,
+	citations []domain.JournalCitation,
+) (int64, error) {
+	tracer := otel.Tracer("repo.journal_citation")
+	ctx, span := tracer.Start(ctx, "JournalCitationRepository.ImportCitations")
+	defer span.End()
+
+	span.SetAttributes(attribute.Int("query.citation_count", len(citations)))
+
+	tx, err := u.Conn.Begin(ctx)
+	if err != nil {
+		span.RecordError(err)
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx, `
+        CREATE TEMPORARY TABLE imported_citations (citing_pmid BIGINT, cited_pmid BIGINT)
+        ON COMMIT DROP`)
+	if err != nil {
+		span.RecordError(err)
+		return 0, err
+	}
+
+	rows := make([][]any, len(citations))
+	for i, c := range citations {
+		rows[i] = []any{c.CitingPMID, c.CitedPMID}
+	}
+	_, err = tx.CopyFrom(
+		ctx,
+		pgx.Identifier{"imported_citations"},
+		[]string{"citing_pmid", "cited_pmid"},
+		pgx.CopyFromRows(rows),
+	)
+	if err != nil {
+		span.RecordError(err)
+		return 0, err
+	}
+
+	tag, err := tx.Exec(ctx, `
+        INSERT INTO journal_citations (citing_pmid, cited_pmid)
+        SELECT DISTINCT i.citing_pmid, i.cited_pmid
+        FROM imported_citations i
+        INNER JOIN journals j ON j.pmid = i.citing_pmid
+        WHERE i.citing_pmid <> i.cited_pmid
+        ON CONFLICT DO NOTHING`)
+	if err != nil {
+		span.RecordError(err)
+		return 0, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		span.RecordError(err)
+		return 0, err
+	}
+
+	return tag.RowsAffected(), nil
+}
+
+// GetCitationGraph returns the PMIDs of the journals of the corpus and every
+// stored citation.
+func (u *JournalCitationRepository) GetCitationGraph(
+	ctx context.Context,
+) ([]int64, []domain.JournalCitation, error) {
+	tracer := otel.Tracer("repo.journal_citation")
+	ctx, span := tracer.Start(ctx, "JournalCitationRepository.GetCitationGraph")
+	defer span.End()
+
+	rows, err := u.Conn.Query(ctx, "SELECT pmid FROM journals")
+	if err != nil {
+		span.RecordError(err)
+		return nil, nil, err
+	}
+	pmids, err := pgx.CollectRows(rows, pgx.RowTo[int64])
+	if err != nil {
+		span.RecordError(err)
+		return nil, nil, err
+	}
+
+	rows, err = u.Conn.Query(ctx, "SELECT citing_pmid, cited_pmid FROM journal_citations")
+	if err != nil {
+		span.RecordError(err)
+		return nil, nil, err
+	}
+	citations, err := pgx.CollectRows(rows, pgx.RowToStructByName[domain.JournalCitation])
+	if err != nil {
+		span.RecordError(err)
+		return nil, nil, err
+	}
+
+	span.SetAttributes(attribute.Int("query.pmid_count", len(pmids)))
+	span.SetAttributes(attribute.Int("query.citation_count", len(citations)))
+	return pmids, citations, nil
+}
+
+// ReplaceJournalSignals replaces every stored value of the signal with the
+// given name by signals.
+func (u *JournalCitationRepository) ReplaceJournalSignals(
+	ctx context.Context,
+	name string,
+	signals []domain.JournalSignal,
+) error {
+	tracer := otel.Tracer("repo.journal_citation")
+	ctx, span := tracer.Start(ctx, "JournalCitationRepository.ReplaceJournalSignals")
+	defer span.End()
+
+	span.SetAttributes(attribute.String("query.signal", name))
+	span.SetAttributes(attribute.Int("query.pmid_count", len(signals)))
+	if err := replaceJournalSignals(ctx, u.Conn, name, signals); err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,571 @@
+package postgres
+
+import (
+	"fmt"
+	"go-app/domain"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// lexicalDocument is the weighted (title > abstract > content) text search
+// document lexical candidates are matched and ranked against. It is a stored
+// generated column backed by a GIN index.
+const lexicalDocument = `search_vector`
+
+func embeddingTable(vType domain.VectorType) string {
+	switch vType {
+	case domain.GeneralVectorType:
+		return "journal_generalist_embeddings"
+	case domain.SpecialistVectorType:
+		return "journal_specialist_embeddings"
+	}
+	return ""
+}
+
+// chunkEmbeddingColumn returns the column of journal_chunks holding the
+// embeddings of vType.
+func chunkEmbeddingColumn(vType domain.VectorType) string {
+	switch vType {
+	case domain.GeneralVectorType:
+		return "generalist_embeddings"
+	case domain.SpecialistVectorType:
+		return "specialist_embeddings"
+	}
+	return ""
+}
+
+// vectorOperator returns the pgvector distance operator of metric. ORDER BY
+// must use it directly, ascending, for the HNSW index to be used.
+func vectorOperator(metric domain.DistanceMetric) string {
+	switch metric {
+	case domain.L2Metric:
+		return "<->"
+	case domain.InnerProductMetric:
+		return "<#>"
+	}
+	return "<=>"
+}
+
+// vectorScore returns the expression scoring column against param with
+// metric, oriented so that higher is better.
+func vectorScore(metric domain.DistanceMetric, column, param string) string {
+	switch metric {
+	case domain.L2Metric:
+		return fmt.Sprintf("-(%s %s %s)", column, vectorOperator(metric), param)
+	case domain.InnerProductMetric:
+		// <#> is the negative inner product.
+		return fmt.Sprintf("(%s %s %s) * -1", column, vectorOperator(metric), param)
+	}
+	return fmt.Sprintf("1 - (%s <=> %s)", column, param)
+}
+
+// vectorOrder returns the ORDER BY expression ranking column nearest first
+// against param with metric.
+func vectorOrder(metric domain.DistanceMetric, column, param string) string {
+	return fmt.Sprintf("%s %s %s", column, vectorOperator(metric), param)
+}
+
+// keysetCondition returns the predicate selecting the rows after the cursor
+// bound to @cursor_score and @cursor_pmid. Ranked pages are ordered by score
+// descending then PMID ascending; unranked pages (empty score) by PMID only.
+func keysetCondition(score, pmidColumn string) string {
+	if score == "" {
+		return fmt.Sprintf("%s > @cursor_pmid", pmidColumn)
+	}
+	return fmt.Sprintf(
+		"(%[1]s < @cursor_score OR (%[1]s = @cursor_score AND %[2]s > @cursor_pmid))",
+		score, pmidColumn,
+	)
+}
+
+// journalColumns are the columns scanned into the fields of domain.Journal
+// besides pmid. expression selects the field from the journals table, whose
+// columns are prefixed by %[1]s and whose pmid is %[2]s; empty is selected
+// in place of the fields a field selection leaves out, so that they are never
+// read.
+var journalColumns = []struct {
+	name, expression, empty string
+}{
+	{"title", "%[1]stitle", "''::varchar"},
+	{"abstract", "%[1]sabstract", "''::varchar"},
+	{"content", "%[1]scontent", "''::text"},
+	{"mesh_terms", "%[1]smesh_terms", "NULL::varchar[]"},
+	{"authors", `(
+                SELECT COALESCE(jsonb_agg(jsonb_build_object(
+                    'name', a.name,
+                    'last_name', a.last_name,
+                    'fore_name', a.fore_name,
+                    'affiliations', a.affiliations
+                ) ORDER BY a.position), '[]')
+                FROM journal_authors a
+                WHERE a.pmid = %[2]s
+            )`, "'[]'::jsonb"},
+	{"journal_name", "%[1]sjournal_name", "''::varchar"},
+	{"publication_date", "COALESCE(to_char(%[1]spublication_date, 'YYYY-MM-DD'), '')", "''::text"},
+	{"doi", "COALESCE(%[1]sdoi, '')", "''::varchar"},
+	{"pmcid", "COALESCE(%[1]spmcid, '')", "''::varchar"},
+	{"publication_types", "%[1]spublication_types", "'{}'::varchar[]"},
+	{"language", "%[1]slanguage", "''::varchar"},
+}
+
+// journalPMID returns the pmid column of the journals table qualified by
+// table, for correlated subqueries which have a pmid of their own.
+func journalPMID(table string) string {
+	if table == "" {
+		return "journals.pmid"
+	}
+	return table + "pmid"
+}
+
+// journalColumnList returns the select list of the journal columns of table
+// (e.g. "j.", or "" when unambiguous), replacing the ones missing from fields
+// by empty values.
+func journalColumnList(fields []string, table string) string {
+	pmid := journalPMID(table)
+	columns := make([]string, 0, len(journalColumns))
+	for _, column := range journalColumns {
+		if domain.HasField(fields, column.name) {
+			columns = append(columns, fmt.Sprintf(column.expression, table, pmid)+" as "+column.name)
+		} else {
+			columns = append(columns, column.empty+" as "+column.name)
+		}
+	}
+	return strings.Join(columns, ", ")
+}
+
+// journalFilterConditions returns the predicates on the journals table that
+// every search mode applies on top of its own matching, binding their values
+// into args. table qualifies the columns of journals, as in journalColumnList.
+func journalFilterConditions(filter *domain.JournalFilter, table string, args pgx.StrictNamedArgs) ([]string, error) {
+	if filter == nil {
+		return nil, nil
+	}
+
+	var conditions []string
+	if len(filter.MeSHAny) > 0 {
+		conditions = append(conditions, "mesh_terms && @mesh_any::varchar[]")
+		args["mesh_any"] = filter.MeSHAny
+	}
+	if len(filter.MeSHAll) > 0 {
+		conditions = append(conditions, "mesh_terms @> @mesh_all::varchar[]")
+		args["mesh_all"] = filter.MeSHAll
+	}
+	if len(filter.MeSHNone) > 0 {
+		conditions = append(conditions, "NOT (COALESCE(mesh_terms, '{}') && @mesh_none::varchar[])")
+		args["mesh_none"] = filter.MeSHNone
+	}
+
+	from, before, err := filter.PublishedRange()
+	if err != nil {
+		return nil, err
+	}
+	if from != nil {
+		conditions = append(conditions, fmt.Sprintf("%spublication_date >= @published_from", table))
+		args["published_from"] = *from
+	}
+	if before != nil {
+		conditions = append(conditions, fmt.Sprintf("%spublication_date < @published_before", table))
+		args["published_before"] = *before
+	}
+	if filter.Author != "" {
+		conditions = append(conditions, fmt.Sprintf(`EXISTS (
+                SELECT 1 FROM journal_authors a
+                WHERE a.pmid = %s AND (lower(a.name) = lower(@author) OR lower(a.last_name) = lower(@author))
+            )`, journalPMID(table)))
+		args["author"] = filter.Author
+	}
+	if filter.JournalName != "" {
+		conditions = append(conditions, fmt.Sprintf("lower(%sjournal_name) = lower(@journal_name)", table))
+		args["journal_name"] = filter.JournalName
+	}
+	if len(filter.PublicationTypes) > 0 {
+		conditions = append(conditions, fmt.Sprintf("%spublication_types && @publication_types::varchar[]", table))
+		args["publication_types"] = filter.PublicationTypes
+	}
+
+	return conditions, nil
+}
+
+// journalListQuery builds the statement listing the journals matched by
+// filter, scanned into domain.JournalResponse. Without paginate the cursor,
+// LIMIT and OFFSET are left out so the statement covers every match.
+func journalListQuery(
+	filter *domain.JournalFilter,
+	embeddings domain.QueryEmbeddings,
+	paginate bool,
+) (string, pgx.StrictNamedArgs, error) {
+	if filter.IsFused() && embeddings != nil {
+		return fusedJournalListQuery(filter, embeddings, paginate)
+	}
+	if filter != nil && filter.Passages && embeddings != nil {
+		return passageJournalListQuery(filter, embeddings, paginate)
+	}
+
+	var after *domain.JournalCursor
+	if paginate {
+		var err error
+		if after, err = filter.After(); err != nil {
+			return "", nil, err
+		}
+	}
+
+	isVector := filter != nil && filter.VSearch != "" && embeddings != nil
+	table := ""
+	if isVector {
+		table = "j."
+	}
+
+	lexicalScore := "0::float8"
+	args := pgx.StrictNamedArgs{}
+	var search *lexicalSearch
+	if filter != nil && filter.Search != "" {
+		var err error
+		if search, err = newLexicalSearch(filter, table, args); err != nil {
+			return "", nil, err
+		}
+		lexicalScore = search.score()
+	}
+
+	// score is the relevance the page is ordered by (descending, ties broken
+	// by PMID), or empty for plain listings ordered by PMID.
+	score, pmidColumn := "", "pmid"
+	query := fmt.Sprintf(`
+		SELECT
+            pmid,
+            %s,
+            0::float8 as distance,
+            %s as lexical_score,
+            NULL::jsonb as passage
+		FROM journals`, journalColumnList(filter.SelectedFields(), ""), lexicalScore)
+
+	if isVector {
+		query = fmt.Sprintf(`
+            SELECT
+                j.pmid,
+                %s,
+                %s as distance,
+                %s as lexical_score,
+                NULL::jsonb as passage
+            FROM journals j
+            INNER JOIN %s je ON j.pmid = je.pmid
+        `, journalColumnList(filter.SelectedFields(), "j."), vectorScore(filter.Type.Metric(), "je.embeddings", "@query"),
+			lexicalScore, embeddingTable(filter.Type))
+		args["query"] = embeddings[filter.Type]
+		score, pmidColumn = vectorScore(filter.Type.Metric(), "je.embeddings", "@query"), "j.pmid"
+	} else if filter != nil && filter.Search != "" {
+		score = lexicalScore
+	}
+
+	conditions, err := journalFilterConditions(filter, table, args)
+	if err != nil {
+		return "", nil, err
+	}
+	if search != nil {
+		conditions = append(conditions, search.match())
+	}
+	if filter != nil && filter.MinScore != nil && score != "" {
+		conditions = append(conditions, fmt.Sprintf("%s >= @min_score", score))
+		args["min_score"] = *filter.MinScore
+	}
+	if after != nil {
+		conditions = append(conditions, keysetCondition(score, pmidColumn))
+		args["cursor_pmid"] = after.PMID
+		if after.Score != nil {
+			args["cursor_score"] = *after.Score
+		}
+	}
+
+	if len(conditions) > 0 {
+		query += fmt.Sprintf(" WHERE %s", strings.Join(conditions, " AND "))
+	}
+
+	if !paginate {
+		return query, args, nil
+	}
+
+	switch {
+	case isVector:
+		// Order by the raw distance operator so the HNSW index can be used.
+		query += fmt.Sprintf(" ORDER BY %s, j.pmid ", vectorOrder(filter.Type.Metric(), "je.embeddings", "@query"))
+	case score != "":
+		query += " ORDER BY lexical_score DESC, pmid "
+	default:
+		query += " ORDER BY pmid "
+	}
+
+	if filter != nil && filter.Limit != nil {
+		query += " LIMIT @limit"
+		args["limit"] = *filter.Limit
+		if after == nil && filter.Page != nil {
+			query += " OFFSET @offset"
+			args["offset"] = *filter.Page * *filter.Limit
+		}
+	}
+
+	return query, args, nil
+}
+
+// fusedJournalListQuery retrieves several candidate lists independently and
+// fuses them according to filter.Fusion: the lexical list of a hybrid search
+// and one semantic list per model of filter.Type. The fused score is returned
+// as the distance of each journal.
+func fusedJournalListQuery(
+	filter *domain.JournalFilter,
+	embeddings domain.QueryEmbeddings,
+	paginate bool,
+) (string, pgx.StrictNamedArgs, error) {
+	limit, offset := 10, 0
+	if filter.Limit != nil {
+		limit = *filter.Limit
+	}
+	if filter.Page != nil {
+		offset = *filter.Page * limit
+	}
+
+	lexicalWeight, vectorWeight := 1.0, 1.0
+	if filter.LexicalWeight != nil {
+		lexicalWeight = *filter.LexicalWeight
+	}
+	if filter.VectorWeight != nil {
+		vectorWeight = *filter.VectorWeight
+	}
+
+	args := pgx.StrictNamedArgs{
+		"candidates": max(domain.DefaultHybridCandidates, offset+limit),
+	}
+
+	conditions, err := journalFilterConditions(filter, "j.", args)
+	if err != nil {
+		return "", nil, err
+	}
+	var search *lexicalSearch
+	if filter.Search != "" {
+		if search, err = newLexicalSearch(filter, "j.", args); err != nil {
+			return "", nil, err
+		}
+	}
+	if search != nil && !filter.IsHybrid() {
+		// Without hybrid fusion the lexical query only prunes the semantic
+		// candidates, as in a single model vector search.
+		conditions = append(conditions, search.match())
+	}
+	where := ""
+	if len(conditions) > 0 {
+		where = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	// Every list ranks its candidates and min-max normalizes their scores.
+	const rankedList = `%s AS (
+            SELECT
+                pmid,
+                score,
+                ROW_NUMBER() OVER (ORDER BY score DESC, pmid) AS rank,
+                COALESCE(
+                    (score - MIN(score) OVER ()) / NULLIF(MAX(score) OVER () - MIN(score) OVER (), 0),
+                    1
+                ) AS norm_score
+            FROM (%s) c
+        )`
+
+	var lists, contributions []string
+	contribution := func(weight string) string {
+		if filter.Fusion == domain.WeightedFusion {
+			return fmt.Sprintf("@%s::float8 * norm_score", weight)
+		}
+		return fmt.Sprintf("@%s::float8 / (@rrf_k::int + rank)", weight)
+	}
+
+	if filter.IsHybrid() {
+		lexicalWhere := ""
+		if len(conditions) > 0 {
+			lexicalWhere = " AND " + strings.Join(conditions, " AND ")
+		}
+		lists = append(lists, fmt.Sprintf(rankedList, "lexical", fmt.Sprintf(`
+                SELECT j.pmid, %s AS score
+                FROM journals j
+                WHERE %s%s
+                ORDER BY score DESC
+                LIMIT @candidates`, search.score(), search.match(), lexicalWhere)))
+		contributions = append(contributions, fmt.Sprintf(
+			"SELECT pmid, %s AS score, score AS lexical_score FROM lexical", contribution("lexical_weight"),
+		))
+		args["lexical_weight"] = lexicalWeight
+	}
+
+	for _, model := range filter.Type.Models() {
+		name, param := "semantic_"+string(model), "query_"+string(model)
+		lists = append(lists, fmt.Sprintf(rankedList, name, fmt.Sprintf(`
+                SELECT je.pmid, %s AS score
+                FROM %s je
+                INNER JOIN journals j ON j.pmid = je.pmid
+                %s
+                ORDER BY %s
+                LIMIT @candidates`,
+			vectorScore(model.Metric(), "je.embeddings", "@"+param), embeddingTable(model), where,
+			vectorOrder(model.Metric(), "je.embeddings", "@"+param))))
+		contributions = append(contributions, fmt.Sprintf(
+			"SELECT pmid, %s AS score, 0::float8 AS lexical_score FROM %s", contribution(name+"_weight"), name,
+		))
+		args[param] = embeddings[model]
+		args[name+"_weight"] = vectorWeight * filter.ModelWeight(model)
+	}
+
+	if filter.Fusion != domain.WeightedFusion {
+		rrfK := domain.DefaultRRFK
+		if filter.RRFK != nil {
+			rrfK = *filter.RRFK
+		}
+		args["rrf_k"] = rrfK
+	}
+
+	lexicalScore := "f.lexical_score"
+	if search != nil && !filter.IsHybrid() {
+		lexicalScore = search.score()
+	}
+
+	var fusedConditions []string
+	if filter.MinScore != nil {
+		fusedConditions = append(fusedConditions, "f.score >= @min_score")
+		args["min_score"] = *filter.MinScore
+	}
+
+	pagination := ""
+	if len(fusedConditions) > 0 {
+		pagination = "WHERE " + strings.Join(fusedConditions, " AND ")
+	}
+	if paginate {
+		pagination += `
+        ORDER BY f.score DESC, j.pmid
+        LIMIT @limit OFFSET @offset`
+		args["limit"] = limit
+		args["offset"] = offset
+	}
+
+	query := fmt.Sprintf(`
+        WITH %s,
+        fused AS (
+            SELECT
+                pmid,
+                SUM(score) AS score,
+                MAX(lexical_score) AS lexical_score
+            FROM (
+                %s
+            ) contributions
+            GROUP BY pmid
+        )
+        SELECT
+            j.pmid,
+            %s,
+            f.score as distance,
+            %s as lexical_score,
+            NULL::jsonb as passage
+        FROM fused f
+        INNER JOIN journals j ON j.pmid = f.pmid
+        %s`,
+		strings.Join(lists, ",\n        "), strings.Join(contributions, "\n                UNION ALL\n                "),
+		journalColumnList(filter.SelectedFields(), "j."), lexicalScore, pagination)
+
+	return query, args, nil
+}
+
+// passageJournalListQuery ranks the chunks of journal content against the
+// query embedding of filter.Type and ranks each journal by its best chunk,
+// which is returned as its passage.
+func passageJournalListQuery(
+	filter *domain.JournalFilter,
+	embeddings domain.QueryEmbeddings,
+	paginate bool,
+) (string, pgx.StrictNamedArgs, error) {
+	limit, offset := 10, 0
+	if filter.Limit != nil {
+		limit = *filter.Limit
+	}
+	if filter.Page != nil {
+		offset = *filter.Page * limit
+	}
+
+	column := "c." + chunkEmbeddingColumn(filter.Type)
+	args := pgx.StrictNamedArgs{
+		"query": embeddings[filter.Type],
+		"chunk_candidates": max(
+			domain.DefaultPassageCandidates,
+			domain.PassageCandidatesPerJournal*(offset+limit),
+		),
+	}
+
+	lexicalScore := "0::float8"
+	conditions, err := journalFilterConditions(filter, "j.", args)
+	if err != nil {
+		return "", nil, err
+	}
+	if filter.Search != "" {
+		search, err := newLexicalSearch(filter, "j.", args)
+		if err != nil {
+			return "", nil, err
+		}
+		lexicalScore = search.score()
+		conditions = append(conditions, search.match())
+	}
+	where := ""
+	if len(conditions) > 0 {
+		where = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	var bestConditions []string
+	if filter.MinScore != nil {
+		bestConditions = append(bestConditions, "b.score >= @min_score")
+		args["min_score"] = *filter.MinScore
+	}
+
+	pagination := ""
+	if len(bestConditions) > 0 {
+		pagination = "WHERE " + strings.Join(bestConditions, " AND ")
+	}
+	if paginate {
+		pagination += `
+        ORDER BY b.score DESC, j.pmid
+        LIMIT @limit OFFSET @offset`
+		args["limit"] = limit
+		args["offset"] = offset
+	}
+
+	query := fmt.Sprintf(`
+        WITH ranked AS (
+            SELECT
+                c.pmid,
+                c.chunk_index,
+                c.content,
+                c.start_offset,
+                c.end_offset,
+                %[1]s AS score
+            FROM journal_chunks c
+            INNER JOIN journals j ON j.pmid = c.pmid
+            %[2]s
+            ORDER BY %[3]s
+            LIMIT @chunk_candidates
+        ),
+        best AS (
+            SELECT DISTINCT ON (pmid) *
+            FROM ranked
+            ORDER BY pmid, score DESC, chunk_index
+        )
+        SELECT
+            j.pmid,
+            %[6]s,
+            b.score as distance,
+            %[4]s as lexical_score,
+            jsonb_build_object(
+                'index', b.chunk_index,
+                'text', b.content,
+                'start', b.start_offset,
+                'end', b.end_offset
+            ) as passage
+        FROM best b
+        INNER JOIN journals j ON j.pmid = b.pmid
+        %[5]s`,
+		vectorScore(filter.Type.Metric(), column, "@query"), where, vectorOrder(filter.Type.Metric(), column, "@query"),
+		lexicalScore, pagination, journalColumnList(filter.SelectedFields(), "j."))
+
+	return query, args, nil
+}
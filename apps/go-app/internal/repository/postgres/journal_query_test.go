@@ -0,0 +1,49 @@
+package postgres
+
+import (
+	"go-app/domain"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVectorMetrics(t *testing.T) {
+	cases := []struct {
+		metric    domain.DistanceMetric
+		operator  string
+		score     string
+		order     string
+		scoreType domain.ScoreType
+	}{
+		{
+			metric:    domain.CosineMetric,
+			operator:  "<=>",
+			score:     "1 - (e <=> @query)",
+			order:     "e <=> @query",
+			scoreType: domain.CosineSimilarityScore,
+		},
+		{
+			metric:    domain.L2Metric,
+			operator:  "<->",
+			score:     "-(e <-> @query)",
+			order:     "e <-> @query",
+			scoreType: domain.NegativeL2DistanceScore,
+		},
+		{
+			metric:    domain.InnerProductMetric,
+			operator:  "<#>",
+			score:     "(e <#> @query) * -1",
+			order:     "e <#> @query",
+			scoreType: domain.InnerProductScore,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(string(tc.metric), func(t *testing.T) {
+			assert.Equal(t, tc.operator, vectorOperator(tc.metric))
+			assert.Equal(t, tc.score, vectorScore(tc.metric, "e", "@query"))
+			assert.Equal(t, tc.order, vectorOrder(tc.metric, "e", "@query"))
+			assert.Equal(t, tc.scoreType, tc.metric.ScoreType())
+		})
+	}
+}
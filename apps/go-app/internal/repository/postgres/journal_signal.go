@@ -0,0 +1,100 @@
+package postgres
+
+import (
+	"context"
+	"go-app/domain"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// GetJournalSignals returns the signals with the given names of the journals
+// with the given PMIDs. Journals lacking a signal have no row for it.
+func (u *JournalRepository) GetJournalSignals(
+	ctx context.Context,
+	pmids []int64,
+	names []string,
+) ([]domain.JournalSignal, error) {
+	tracer := otel.Tracer("repo.journal")
+	ctx, span := tracer.Start(ctx, "JournalRepository.GetJournalSignals")
+	defer span.End()
+
+	query := `
+        SELECT pmid, name, value
+        FROM journal_signals
+        WHERE pmid = ANY(@pmids) AND name = ANY(@names)`
+	span.SetAttributes(attribute.String("query.statement", query))
+	span.SetAttributes(attribute.Int("query.pmid_count", len(pmids)))
+	rows, err := u.Conn.Query(ctx, query, pgx.StrictNamedArgs{"pmids": pmids, "names": names})
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	signals, err := pgx.CollectRows(rows, pgx.RowToStructByName[domain.JournalSignal])
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	return signals, nil
+}
+
+// ReplaceJournalSignals replaces every stored value of the signal with the
+// given name by signals.
+func (u *JournalRepository) ReplaceJournalSignals(
+	ctx context.Context,
+	name string,
+	signals []domain.JournalSignal,
+) error {
+	tracer := otel.Tracer("repo.journal")
+	ctx, span := tracer.Start(ctx, "JournalRepository.ReplaceJournalSignals")
+	defer span.End()
+
+	span.SetAttributes(attribute.String("query.signal", name))
+	span.SetAttributes(attribute.Int("query.pmid_count", len(signals)))
+	if err := replaceJournalSignals(ctx, u.Conn, name, signals); err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	return nil
+}
+
+// replaceJournalSignals deletes the stored values of the signal and copies
+// signals in their place, in one transaction so that rankings never see the
+// signal half-written.
+func replaceJournalSignals(
+	ctx context.Context,
+	conn *pgxpool.Pool,
+	name string,
+	signals []domain.JournalSignal,
+) error {
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, "DELETE FROM journal_signals WHERE name = $1", name); err != nil {
+		return err
+	}
+
+	rows := make([][]any, len(signals))
+	for i, s := range signals {
+		rows[i] = []any{s.PMID, name, s.Value}
+	}
+	_, err = tx.CopyFrom(
+		ctx,
+		pgx.Identifier{"journal_signals"},
+		[]string{"pmid", "name", "value"},
+		pgx.CopyFromRows(rows),
+	)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
@@ -0,0 +1,183 @@
+package postgres
+
+import (
+	"fmt"
+	"go-app/domain"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// lexicalSearch is a boolean search query compiled into SQL. The value of
+// every term is bound as a parameter named after its position, so match and
+// rank can be used together or alone.
+type lexicalSearch struct {
+	query *domain.SearchNode
+	table string // qualifies the columns of journals, e.g. "j."
+	args  pgx.StrictNamedArgs
+	fuzzy bool // matches the keywords by trigrams instead, see fuzzyMatch
+}
+
+// newLexicalSearch compiles the search of filter, in its fuzzy mode if set.
+func newLexicalSearch(filter *domain.JournalFilter, table string, args pgx.StrictNamedArgs) (*lexicalSearch, error) {
+	search, err := compileSearch(filter.Search, table, args)
+	if err != nil {
+		return nil, err
+	}
+	search.fuzzy = filter.Fuzzy
+	return search, nil
+}
+
+// compileSearch parses search, binding the values of its terms into args.
+func compileSearch(search, table string, args pgx.StrictNamedArgs) (*lexicalSearch, error) {
+	query, err := domain.ParseSearchQuery(search)
+	if err != nil {
+		return nil, err
+	}
+	return &lexicalSearch{query: query, table: table, args: args}, nil
+}
+
+// match returns the predicate selecting the journals that satisfy the query.
+// Terms over the whole text are merged into as few tsqueries as possible so
+// that the search_vector index is scanned once per group of them.
+func (s *lexicalSearch) match() string {
+	if s.fuzzy {
+		return s.fuzzyMatch()
+	}
+
+	expression, isTSQuery := s.compile(s.query)
+	if isTSQuery {
+		return fmt.Sprintf("%s @@ %s", lexicalDocument, expression)
+	}
+	return expression
+}
+
+// compile returns the SQL of node, either a tsquery to match against the
+// lexical document or a boolean predicate.
+func (s *lexicalSearch) compile(node *domain.SearchNode) (string, bool) {
+	if node.IsTerm() {
+		return s.term(node)
+	}
+
+	if node.Operator == domain.NotSearchOperator {
+		operand, isTSQuery := s.compile(node.Operands[0])
+		if isTSQuery {
+			return fmt.Sprintf("!!(%s)", operand), true
+		}
+		return fmt.Sprintf("NOT (%s)", operand), false
+	}
+
+	var tsqueries, predicates []string
+	for _, operand := range node.Operands {
+		if expression, isTSQuery := s.compile(operand); isTSQuery {
+			tsqueries = append(tsqueries, expression)
+		} else {
+			predicates = append(predicates, expression)
+		}
+	}
+
+	tsqueryOperator, predicateOperator := " && ", " AND "
+	if node.Operator == domain.OrSearchOperator {
+		tsqueryOperator, predicateOperator = " || ", " OR "
+	}
+	if len(predicates) == 0 {
+		return "(" + strings.Join(tsqueries, tsqueryOperator) + ")", true
+	}
+	if len(tsqueries) > 0 {
+		predicates = append(predicates, fmt.Sprintf(
+			"%s @@ (%s)", lexicalDocument, strings.Join(tsqueries, tsqueryOperator),
+		))
+	}
+	return "(" + strings.Join(predicates, predicateOperator) + ")", false
+}
+
+// term returns the SQL of a term, a tsquery when it matches the whole text.
+func (s *lexicalSearch) term(node *domain.SearchNode) (string, bool) {
+	param := s.bind(node)
+	switch node.Field {
+	case domain.TitleSearchField, domain.AbstractSearchField:
+		// Backed by expression indexes, like the whole text search_vector.
+		return fmt.Sprintf(
+			"to_tsvector('english', %s%s) @@ %s", s.table, node.Field, termQuery(node, param),
+		), false
+	case domain.MeSHSearchField:
+		return fmt.Sprintf("%smesh_terms @> ARRAY[%s]::varchar[]", s.table, param), false
+	case domain.PMIDSearchField:
+		return fmt.Sprintf("%spmid = %s", s.table, param), false
+	}
+	return termQuery(node, param), true
+}
+
+// bind binds the value of a term, returning its parameter.
+func (s *lexicalSearch) bind(node *domain.SearchNode) string {
+	name := fmt.Sprintf("search_%d", node.Position)
+	if node.Field == domain.PMIDSearchField {
+		s.args[name] = node.PMID
+	} else {
+		s.args[name] = node.Text
+	}
+	return "@" + name
+}
+
+// termQuery returns the tsquery of a text term bound to param.
+func termQuery(node *domain.SearchNode, param string) string {
+	if node.Phrase {
+		return fmt.Sprintf("phraseto_tsquery('english', %s)", param)
+	}
+	return fmt.Sprintf("plainto_tsquery('english', %s)", param)
+}
+
+// rank returns the tsquery of the text terms that are not negated, whatever
+// their field, which lexical scores and highlights are computed against. It
+// is empty when the query has none, e.g. a MeSH or PMID lookup.
+func (s *lexicalSearch) rank() string {
+	var tsqueries []string
+	var walk func(node *domain.SearchNode, negated bool)
+	walk = func(node *domain.SearchNode, negated bool) {
+		if node.IsTerm() {
+			if !negated && node.Field.IsText() {
+				tsqueries = append(tsqueries, termQuery(node, s.bind(node)))
+			}
+			return
+		}
+		for _, operand := range node.Operands {
+			walk(operand, negated != (node.Operator == domain.NotSearchOperator))
+		}
+	}
+	walk(s.query, false)
+
+	if len(tsqueries) == 0 {
+		return ""
+	}
+	return "(" + strings.Join(tsqueries, " || ") + ")"
+}
+
+// fuzzyMatch returns the predicate of the fuzzy mode, which tolerates
+// misspellings: journals whose title, or one of whose MeSH headings, contains
+// words similar to the keywords of the query by pg_trgm's word similarity
+// (above pg_trgm.word_similarity_threshold, 0.6 by default). MeSH headings
+// are looked up in the search_vocabulary, whose trigram index serves <%.
+// Operators and field prefixes of the query are not applied.
+func (s *lexicalSearch) fuzzyMatch() string {
+	s.args["fuzzy"] = s.query.Keywords()
+	return fmt.Sprintf(`(@fuzzy <%% %[1]stitle OR %[1]smesh_terms && ARRAY(
+                SELECT term FROM search_vocabulary WHERE source = 'mesh' AND @fuzzy <%% term
+            )::varchar[])`, s.table)
+}
+
+// score returns the lexical score expression of the query.
+func (s *lexicalSearch) score() string {
+	if s.fuzzy {
+		s.args["fuzzy"] = s.query.Keywords()
+		return fmt.Sprintf(`GREATEST(
+                word_similarity(@fuzzy, %[1]stitle),
+                (SELECT max(word_similarity(@fuzzy, term)) FROM unnest(%[1]smesh_terms) term)
+            )::float8`, s.table)
+	}
+
+	rank := s.rank()
+	if rank == "" {
+		return "0::float8"
+	}
+	return fmt.Sprintf("ts_rank_cd(%s, %s)::float8", lexicalDocument, rank)
+}
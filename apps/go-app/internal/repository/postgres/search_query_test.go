@@ -0,0 +1,78 @@
+package postgres
+
+import (
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLexicalSearch_Match(t *testing.T) {
+	cases := []struct {
+		name   string
+		search string
+		match  string
+		args   pgx.StrictNamedArgs
+	}{
+		{
+			name:   "Merges phrases and words into one tsquery",
+			search: `"heart attack" aspirin`,
+			match:  "search_vector @@ (phraseto_tsquery('english', @search_0) && plainto_tsquery('english', @search_15))",
+			args:   pgx.StrictNamedArgs{"search_0": "heart attack", "search_15": "aspirin"},
+		},
+		{
+			name:   "Matches fields with their own predicates",
+			search: `title:stroke mesh:Mice`,
+			match: "(to_tsvector('english', j.title) @@ plainto_tsquery('english', @search_6)" +
+				" AND j.mesh_terms @> ARRAY[@search_18]::varchar[])",
+			args: pgx.StrictNamedArgs{"search_6": "stroke", "search_18": "Mice"},
+		},
+		{
+			name:   "Negates a tsquery within the tsquery",
+			search: `aspirin NOT stroke`,
+			match:  "search_vector @@ (plainto_tsquery('english', @search_0) && !!(plainto_tsquery('english', @search_12)))",
+			args:   pgx.StrictNamedArgs{"search_0": "aspirin", "search_12": "stroke"},
+		},
+		{
+			name:   "Negates a field predicate and keeps the words in one tsquery",
+			search: `aspirin -mesh:Mice`,
+			match:  "(NOT (j.mesh_terms @> ARRAY[@search_14]::varchar[]) AND search_vector @@ (plainto_tsquery('english', @search_0)))",
+			args:   pgx.StrictNamedArgs{"search_0": "aspirin", "search_14": "Mice"},
+		},
+		{
+			name:   "Binds PMIDs as integers",
+			search: `pmid:(12 OR 34)`,
+			match:  "(j.pmid = @search_6 OR j.pmid = @search_12)",
+			args:   pgx.StrictNamedArgs{"search_6": int64(12), "search_12": int64(34)},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			args := pgx.StrictNamedArgs{}
+			search, err := compileSearch(tc.search, "j.", args)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.match, search.match())
+			assert.Equal(t, tc.args, args)
+		})
+	}
+}
+
+func TestLexicalSearch_Score(t *testing.T) {
+	t.Run("Ranks by the text terms that are not negated", func(t *testing.T) {
+		args := pgx.StrictNamedArgs{}
+		search, err := compileSearch(`title:"septic shock" NOT aspirin mesh:Sepsis`, "j.", args)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "ts_rank_cd(search_vector, (phraseto_tsquery('english', @search_6)))::float8", search.score())
+		assert.Equal(t, pgx.StrictNamedArgs{"search_6": "septic shock"}, args)
+	})
+
+	t.Run("Scores PMID lookups as 0", func(t *testing.T) {
+		search, err := compileSearch(`pmid:12`, "", pgx.StrictNamedArgs{})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "0::float8", search.score())
+	})
+}
@@ -0,0 +1,72 @@
+package postgres
+
+import (
+	"context"
+	"go-app/domain"
+	"strconv"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// applyRecallProfile sets the HNSW settings of profile for the rest of tx.
+func applyRecallProfile(ctx context.Context, tx pgx.Tx, profile domain.RecallProfile) error {
+	settings := profile.HNSWSettings()
+	if settings.Exact {
+		_, err := tx.Exec(ctx, "SELECT set_config('enable_indexscan', 'off', true)")
+		return err
+	}
+
+	// set_config(..., true) is the parameterizable form of SET LOCAL.
+	_, err := tx.Exec(ctx, `
+        SELECT
+            set_config('hnsw.ef_search', $1, true),
+            set_config('hnsw.iterative_scan', $2, true),
+            set_config('hnsw.max_scan_tuples', $3, true)`,
+		strconv.Itoa(settings.EfSearch),
+		settings.IterativeScan,
+		strconv.Itoa(settings.MaxScanTuples),
+	)
+	return err
+}
+
+// collectSearch runs query and collects its rows with scan. When profile is
+// set the query runs in a read-only transaction configured by
+// applyRecallProfile, since the settings only last for that transaction.
+func collectSearch[T any](
+	ctx context.Context,
+	conn *pgxpool.Pool,
+	profile domain.RecallProfile,
+	scan pgx.RowToFunc[T],
+	query string,
+	args ...any,
+) ([]T, error) {
+	if profile == "" {
+		rows, err := conn.Query(ctx, query, args...)
+		if err != nil {
+			return nil, err
+		}
+		return pgx.CollectRows(rows, scan)
+	}
+
+	tx, err := conn.BeginTx(ctx, pgx.TxOptions{AccessMode: pgx.ReadOnly})
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := applyRecallProfile(ctx, tx, profile); err != nil {
+		return nil, err
+	}
+
+	rows, err := tx.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	result, err := pgx.CollectRows(rows, scan)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, tx.Commit(ctx)
+}
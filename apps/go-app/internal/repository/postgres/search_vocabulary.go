@@ -0,0 +1,152 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"go-app/domain"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// GetSearchSuggestions returns up to size "did you mean" search queries for
+// keywords that matched nothing: the keywords with each word replaced by the
+// closest title word, then the MeSH headings closest to the keywords. Both
+// come from the search_vocabulary, so they lag behind journals until it is
+// refreshed.
+func (u *JournalRepository) GetSearchSuggestions(
+	ctx context.Context,
+	keywords string,
+	size int,
+) ([]string, error) {
+	tracer := otel.Tracer("repo.journal")
+	ctx, span := tracer.Start(ctx, "JournalRepository.GetSearchSuggestions")
+	defer span.End()
+
+	words := strings.Fields(strings.ToLower(keywords))
+	if len(words) == 0 || size < 1 {
+		return nil, nil
+	}
+
+	query := `
+        SELECT COALESCE(v.term, w.word)
+        FROM unnest(@words::text[]) WITH ORDINALITY w(word, n)
+        LEFT JOIN LATERAL (
+            SELECT term
+            FROM search_vocabulary
+            WHERE source = 'title' AND term % w.word
+            ORDER BY term <-> w.word, frequency DESC
+            LIMIT 1
+        ) v ON true
+        ORDER BY w.n`
+	span.SetAttributes(attribute.String("query.statement", query))
+	rows, err := u.Conn.Query(ctx, query, pgx.StrictNamedArgs{"words": words})
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+	corrected, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	var suggestions []string
+	if suggestion := strings.Join(corrected, " "); suggestion != strings.Join(words, " ") {
+		suggestions = append(suggestions, suggestion)
+	}
+
+	query = `
+        SELECT term
+        FROM search_vocabulary
+        WHERE source = 'mesh' AND term % @keywords
+        ORDER BY term <-> @keywords, frequency DESC
+        LIMIT @size`
+	rows, err = u.Conn.Query(ctx, query, pgx.StrictNamedArgs{"keywords": keywords, "size": size})
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+	headings, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+	for _, heading := range headings {
+		// A quote would end the phrase of the suggested query early.
+		if len(suggestions) < size && !strings.Contains(heading, `"`) {
+			suggestions = append(suggestions, fmt.Sprintf(`mesh:"%s"`, heading))
+		}
+	}
+
+	return suggestions, nil
+}
+
+// likePattern escapes the LIKE wildcards of prefix and matches what starts
+// with it.
+var likePattern = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// GetSuggestions returns up to limit completions of prefix, case-insensitive:
+// the journal titles and the MeSH headings of the search_vocabulary starting
+// with it. Both are looked up through prefix indexes on their lowercase text,
+// so that typeahead stays fast; each source is capped to limit before the
+// completions are ranked together.
+func (u *JournalRepository) GetSuggestions(ctx context.Context, prefix string, limit int) ([]domain.Suggestion, error) {
+	tracer := otel.Tracer("repo.journal")
+	ctx, span := tracer.Start(ctx, "JournalRepository.GetSuggestions")
+	defer span.End()
+
+	prefix = strings.ToLower(strings.TrimSpace(prefix))
+	query := `
+        SELECT text, source, pmid, similarity(lower(text), @prefix)::float8 AS score
+        FROM (
+            (
+                SELECT term AS text, 'mesh' AS source, NULL::bigint AS pmid, frequency
+                FROM search_vocabulary
+                WHERE source = 'mesh' AND lower(term) COLLATE "C" LIKE @pattern
+                ORDER BY frequency DESC, term
+                LIMIT @limit
+            )
+            UNION ALL
+            (
+                SELECT title, 'title', pmid, 1
+                FROM journals
+                WHERE lower(title) COLLATE "C" LIKE @pattern
+                -- In index order, so that common prefixes stop early.
+                ORDER BY lower(title) COLLATE "C"
+                LIMIT @limit
+            )
+        ) completions
+        ORDER BY score DESC, frequency DESC, text
+        LIMIT @limit`
+	args := pgx.StrictNamedArgs{
+		"prefix":  prefix,
+		"pattern": likePattern.Replace(prefix) + "%",
+		"limit":   limit,
+	}
+
+	span.SetAttributes(attribute.String("query.statement", query))
+	span.SetAttributes(attribute.String("query.prefix", prefix))
+	rows, err := u.Conn.Query(ctx, query, args)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	suggestions, err := pgx.CollectRows(rows, pgx.RowToStructByName[domain.Suggestion])
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	return suggestions, nil
+}
+
+// RefreshSearchVocabulary rebuilds the search_vocabulary of title words and
+// MeSH headings from the journals, without blocking the searches reading it.
+func (u *JournalRepository) RefreshSearchVocabulary(ctx context.Context) error {
+	_, err := u.Conn.Exec(ctx, "REFRESH MATERIALIZED VIEW CONCURRENTLY search_vocabulary")
+	return err
+}
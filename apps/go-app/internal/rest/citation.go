@@ -0,0 +1,149 @@
+package rest
+
+import (
+	"context"
+	"errors"
+	"go-app/domain"
+	"go-app/internal/logging"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+type CitationService interface {
+	GetCitationNeighbours(
+		ctx context.Context,
+		pmid int64,
+		relation domain.CitationRelation,
+		filter *domain.CitationFilter,
+	) (*domain.CitationList, error)
+}
+
+type CitationHandler struct {
+	Service CitationService
+}
+
+// NewCitationHandler registers the citation graph routes of a journal on the
+// journals group.
+func NewCitationHandler(e *echo.Group, svc CitationService) {
+	handler := &CitationHandler{
+		Service: svc,
+	}
+
+	e.GET("/:pmid/references", handler.GetReferences)
+	e.GET("/:pmid/cited-by", handler.GetCitedBy)
+	e.GET("/:pmid/co-cited", handler.GetCoCited)
+}
+
+// @Summary        Get Journal References
+// @Description    Get the journals a journal cites, the most authoritative first
+// @Tags           Citations
+// @Accept         json
+// @Produce        json
+// @Param          pmid    path        int true "Journal PMID"
+// @Param          filter  query       domain.CitationFilter  false "Page size and page"
+// @Success        200     {object}    domain.ResponseMultipleData[domain.CitationNeighbour] "Successfully retrieved references"
+// @Failure        400     {object}    domain.ResponseMultipleData[domain.Empty]                 "Bad request"
+// @Failure        404     {object}    domain.ResponseMultipleData[domain.Empty]                 "Journal not found"
+// @Failure        500     {object}    domain.ResponseMultipleData[domain.Empty]                 "Internal server error"
+// @Router         /api/v1/journals/{pmid}/references [get]
+func (h *CitationHandler) GetReferences(c echo.Context) error {
+	return h.getCitationNeighbours(c, domain.ReferencesRelation)
+}
+
+// @Summary        Get Citing Journals
+// @Description    Get the journals citing a journal, the most authoritative first
+// @Tags           Citations
+// @Accept         json
+// @Produce        json
+// @Param          pmid    path        int true "Journal PMID"
+// @Param          filter  query       domain.CitationFilter  false "Page size and page"
+// @Success        200     {object}    domain.ResponseMultipleData[domain.CitationNeighbour] "Successfully retrieved citing journals"
+// @Failure        400     {object}    domain.ResponseMultipleData[domain.Empty]                 "Bad request"
+// @Failure        404     {object}    domain.ResponseMultipleData[domain.Empty]                 "Journal not found"
+// @Failure        500     {object}    domain.ResponseMultipleData[domain.Empty]                 "Internal server error"
+// @Router         /api/v1/journals/{pmid}/cited-by [get]
+func (h *CitationHandler) GetCitedBy(c echo.Context) error {
+	return h.getCitationNeighbours(c, domain.CitedByRelation)
+}
+
+// @Summary        Get Co-cited Journals
+// @Description    Get the journals cited together with a journal, the most co-cited first
+// @Tags           Citations
+// @Accept         json
+// @Produce        json
+// @Param          pmid    path        int true "Journal PMID"
+// @Param          filter  query       domain.CitationFilter  false "Page size and page"
+// @Success        200     {object}    domain.ResponseMultipleData[domain.CitationNeighbour] "Successfully retrieved co-cited journals"
+// @Failure        400     {object}    domain.ResponseMultipleData[domain.Empty]                 "Bad request"
+// @Failure        404     {object}    domain.ResponseMultipleData[domain.Empty]                 "Journal not found"
+// @Failure        500     {object}    domain.ResponseMultipleData[domain.Empty]                 "Internal server error"
+// @Router         /api/v1/journals/{pmid}/co-cited [get]
+func (h *CitationHandler) GetCoCited(c echo.Context) error {
+	return h.getCitationNeighbours(c, domain.CoCitedRelation)
+}
+
+func (h *CitationHandler) getCitationNeighbours(c echo.Context, relation domain.CitationRelation) error {
+	tracer := otel.Tracer("http.handler.citation")
+	ctx, span := tracer.Start(c.Request().Context(), "GetCitationNeighboursHandler")
+	defer span.End()
+
+	pmid, err := strconv.ParseInt(c.Param("pmid"), 10, 64)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "invalid PMID")
+		return c.JSON(http.StatusBadRequest, domain.ResponseMultipleData[domain.Empty]{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid journal PMID format",
+		})
+	}
+
+	filter := new(domain.CitationFilter)
+	if err := c.Bind(filter); err != nil {
+		logging.LogWarn(ctx, "Failed to bind citation filter", slog.String("error", err.Error()))
+	}
+
+	span.SetAttributes(attribute.Int64("journal.pmid", pmid))
+	span.SetAttributes(attribute.String("citation.relation", string(relation)))
+	list, err := h.Service.GetCitationNeighbours(ctx, pmid, relation, filter)
+	if err != nil {
+		span.RecordError(err)
+		if errors.Is(err, domain.ErrBadParamInput) {
+			span.SetStatus(codes.Error, "bad request")
+			return c.JSON(http.StatusBadRequest, domain.ResponseMultipleData[domain.Empty]{
+				Code:    http.StatusBadRequest,
+				Message: err.Error(),
+			})
+		}
+		if errors.Is(err, domain.ErrNotFound) {
+			span.SetStatus(codes.Error, "not found")
+			return c.JSON(http.StatusNotFound, domain.ResponseMultipleData[domain.Empty]{
+				Code:    http.StatusNotFound,
+				Message: "Journal not found",
+			})
+		}
+
+		span.SetStatus(codes.Error, "service error")
+		logging.LogError(ctx, err, "get_citation_neighbours")
+		return c.JSON(http.StatusInternalServerError, domain.ResponseMultipleData[domain.Empty]{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to get citations: " + err.Error(),
+		})
+	}
+	neighbours := list.Neighbours
+	if neighbours == nil {
+		neighbours = []domain.CitationNeighbour{}
+	}
+
+	return c.JSON(http.StatusOK, domain.ResponseMultipleData[domain.CitationNeighbour]{
+		Data:    neighbours,
+		Code:    http.StatusOK,
+		Message: "Successfully retrieve citations",
+		Meta:    &list.Meta,
+	})
+}
@@ -2,14 +2,16 @@ package rest
 
 import (
 	"context"
-	"database/sql"
+	"encoding/json"
 	"errors"
 	"go-app/domain"
 	"go-app/internal/logging"
+	"go-app/internal/rest/middleware"
 	"log/slog"
 	"net/http"
+	"strconv"
+	"time"
 
-	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
@@ -17,10 +19,31 @@ import (
 )
 
 type JournalService interface {
-	GetJournalList(ctx context.Context, filter *domain.JournalFilter) ([]domain.JournalResponse, error)
-	GetJournal(ctx context.Context, id uuid.UUID) (*domain.Journal, error)
+	GetJournalList(ctx context.Context, filter *domain.JournalFilter) (*domain.JournalList, error)
+	GetMeSHFacets(ctx context.Context, filter *domain.JournalFacetFilter) ([]domain.FacetCount, error)
+	SuggestJournals(ctx context.Context, filter *domain.SuggestFilter) ([]domain.Suggestion, error)
+	GetJournal(ctx context.Context, pmid int64, fields []string) (*domain.Journal, error)
+	BatchGetJournals(
+		ctx context.Context,
+		req *domain.JournalBatchGetRequest,
+	) (*domain.JournalBatchGetResponse, error)
+	GetSimilarJournals(
+		ctx context.Context,
+		pmid int64,
+		filter *domain.SimilarJournalFilter,
+	) ([]domain.JournalResponse, error)
 }
 
+// SuggestPath is the path of the typeahead endpoint in the journals group.
+const SuggestPath = "/suggest"
+
+// Rate limit of the typeahead endpoint per client, in requests per second
+// and burst size.
+const (
+	SuggestRateLimit = 20.0
+	SuggestRateBurst = 40
+)
+
 type JournalHandler struct {
 	Service JournalService
 }
@@ -31,7 +54,15 @@ func NewJournalHandler(e *echo.Group, svc JournalService) {
 	}
 
 	e.GET("", handler.GetJournalList)
-	e.GET("/:id", handler.GetJournal)
+	e.GET("/facets", handler.GetMeSHFacets)
+	// Typeahead is called on every keystroke, so it has a limit of its own
+	// and is skipped by the global one, see SuggestPath.
+	e.GET(SuggestPath, handler.SuggestJournals, middleware.RateLimitMiddleware(SuggestRateLimit, SuggestRateBurst))
+	// The escaped colon makes ":batchGet" a literal suffix of the group path
+	// (POST /journals:batchGet) instead of a path parameter.
+	e.POST("\\:batchGet", handler.BatchGetJournals)
+	e.GET("/:pmid", handler.GetJournal)
+	e.GET("/:pmid/similar", handler.GetSimilarJournals)
 }
 
 // @Summary        Get Journal List
@@ -44,9 +75,11 @@ func NewJournalHandler(e *echo.Group, svc JournalService) {
 // @Failure        400     {object}    domain.ResponseMultipleData[domain.Empty]              "Bad request"
 // @Failure        401     {object}    domain.ResponseMultipleData[domain.Empty]              "Unauthorized"
 // @Failure        500     {object}    domain.ResponseMultipleData[domain.Empty]              "Internal server error"
+// @Failure        503     {object}    domain.ResponseMultipleData[domain.Empty]              "Passages not indexed"
 // @Router         /api/v1/journals [get]
 func (h *JournalHandler) GetJournalList(c echo.Context) error {
 	ctx := c.Request().Context()
+	start := time.Now()
 
 	filter := new(domain.JournalFilter)
 	if err := c.Bind(filter); err != nil {
@@ -62,22 +95,147 @@ func (h *JournalHandler) GetJournalList(c echo.Context) error {
 		filter.Limit = &limit
 	}
 
-	journals, err := h.Service.GetJournalList(ctx, filter)
+	list, err := h.Service.GetJournalList(ctx, filter)
 	if err != nil {
+		if errors.Is(err, domain.ErrBadParamInput) {
+			return c.JSON(http.StatusBadRequest, domain.ResponseMultipleData[domain.Empty]{
+				Code:    http.StatusBadRequest,
+				Message: err.Error(),
+				Errors:  searchErrorDetails(err),
+			})
+		}
+		if errors.Is(err, domain.ErrPassagesNotIndexed) {
+			return c.JSON(http.StatusServiceUnavailable, domain.ResponseMultipleData[domain.Empty]{
+				Code:    http.StatusServiceUnavailable,
+				Message: err.Error(),
+			})
+		}
+
 		logging.LogError(ctx, err, "get_journal_list")
 		return c.JSON(http.StatusInternalServerError, domain.ResponseMultipleData[domain.Empty]{
 			Code:    http.StatusInternalServerError,
 			Message: "Failed to list journals: " + err.Error(),
 		})
 	}
+	journals := list.Journals
 	if journals == nil {
 		journals = []domain.JournalResponse{}
 	}
+	list.Meta.TookMs = time.Since(start).Milliseconds()
+
+	if fields := filter.SelectedFields(); fields != nil {
+		picked, err := domain.PickFields(journals, fields)
+		if err != nil {
+			logging.LogError(ctx, err, "get_journal_list")
+			return c.JSON(http.StatusInternalServerError, domain.ResponseMultipleData[domain.Empty]{
+				Code:    http.StatusInternalServerError,
+				Message: "Failed to list journals: " + err.Error(),
+			})
+		}
+
+		return c.JSON(http.StatusOK, domain.ResponseMultipleData[map[string]json.RawMessage]{
+			Data:    picked,
+			Code:    http.StatusOK,
+			Message: "Successfully retrieve journal list",
+			Meta:    &list.Meta,
+		})
+	}
 
 	return c.JSON(http.StatusOK, domain.ResponseMultipleData[domain.JournalResponse]{
 		Data:    journals,
 		Code:    http.StatusOK,
 		Message: "Successfully retrieve journal list",
+		Meta:    &list.Meta,
+	})
+}
+
+// @Summary        Get MeSH Facets
+// @Description    Get the most frequent MeSH terms of the journals matching a search
+// @Tags           Journals
+// @Accept         json
+// @Produce        json
+// @Param          filter    query        domain.JournalFacetFilter  true "Journal and facet filters"
+// @Success        200     {object}    domain.ResponseMultipleData[domain.FacetCount] "Successfully retrieved MeSH facets"
+// @Failure        400     {object}    domain.ResponseMultipleData[domain.Empty]              "Bad request"
+// @Failure        500     {object}    domain.ResponseMultipleData[domain.Empty]              "Internal server error"
+// @Router         /api/v1/journals/facets [get]
+func (h *JournalHandler) GetMeSHFacets(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	filter := new(domain.JournalFacetFilter)
+	if err := c.Bind(filter); err != nil {
+		logging.LogWarn(ctx, "Failed to bind journal facet filter", slog.String("error", err.Error()))
+	}
+
+	facets, err := h.Service.GetMeSHFacets(ctx, filter)
+	if err != nil {
+		if errors.Is(err, domain.ErrBadParamInput) {
+			return c.JSON(http.StatusBadRequest, domain.ResponseMultipleData[domain.Empty]{
+				Code:    http.StatusBadRequest,
+				Message: err.Error(),
+				Errors:  searchErrorDetails(err),
+			})
+		}
+
+		logging.LogError(ctx, err, "get_mesh_facets")
+		return c.JSON(http.StatusInternalServerError, domain.ResponseMultipleData[domain.Empty]{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to get MeSH facets: " + err.Error(),
+		})
+	}
+	if facets == nil {
+		facets = []domain.FacetCount{}
+	}
+
+	return c.JSON(http.StatusOK, domain.ResponseMultipleData[domain.FacetCount]{
+		Data:    facets,
+		Code:    http.StatusOK,
+		Message: "Successfully retrieve MeSH facets",
+	})
+}
+
+// @Summary        Suggest Completions
+// @Description    Complete a typeahead prefix with journal titles and MeSH headings, closest first
+// @Tags           Journals
+// @Accept         json
+// @Produce        json
+// @Param          filter    query        domain.SuggestFilter  true "Prefix and number of completions"
+// @Success        200     {object}    domain.ResponseMultipleData[domain.Suggestion] "Successfully retrieved completions"
+// @Failure        400     {object}    domain.ResponseMultipleData[domain.Empty]              "Bad request"
+// @Failure        429     {object}    domain.ResponseMultipleData[domain.Empty]              "Rate limit exceeded"
+// @Failure        500     {object}    domain.ResponseMultipleData[domain.Empty]              "Internal server error"
+// @Router         /api/v1/journals/suggest [get]
+func (h *JournalHandler) SuggestJournals(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	filter := new(domain.SuggestFilter)
+	if err := c.Bind(filter); err != nil {
+		logging.LogWarn(ctx, "Failed to bind suggest filter", slog.String("error", err.Error()))
+	}
+
+	suggestions, err := h.Service.SuggestJournals(ctx, filter)
+	if err != nil {
+		if errors.Is(err, domain.ErrBadParamInput) {
+			return c.JSON(http.StatusBadRequest, domain.ResponseMultipleData[domain.Empty]{
+				Code:    http.StatusBadRequest,
+				Message: err.Error(),
+			})
+		}
+
+		logging.LogError(ctx, err, "suggest_journals")
+		return c.JSON(http.StatusInternalServerError, domain.ResponseMultipleData[domain.Empty]{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to suggest completions: " + err.Error(),
+		})
+	}
+	if suggestions == nil {
+		suggestions = []domain.Suggestion{}
+	}
+
+	return c.JSON(http.StatusOK, domain.ResponseMultipleData[domain.Suggestion]{
+		Data:    suggestions,
+		Code:    http.StatusOK,
+		Message: "Successfully retrieve completions",
 	})
 }
 
@@ -86,33 +244,47 @@ func (h *JournalHandler) GetJournalList(c echo.Context) error {
 // @Tags           Journals
 // @Accept         json
 // @Produce        json
-// @Param          id    path        string true "Journal id"
+// @Param          pmid    path        int true "Journal PMID"
+// @Param          fields  query       string false "Comma separated fields to return, e.g. pmid,title"
 // @Success        200     {object}    domain.ResponseSingleData[domain.Journal] "Successfully retrieved journal"
 // @Failure        400     {object}    domain.ResponseSingleData[domain.Empty]              "Bad request"
 // @Failure        401     {object}    domain.ResponseSingleData[domain.Empty]              "Unauthorized"
+// @Failure        404     {object}    domain.ResponseSingleData[domain.Empty]              "Journal not found"
 // @Failure        500     {object}    domain.ResponseSingleData[domain.Empty]              "Internal server error"
-// @Router         /api/v1/journals/{id} [get]
+// @Router         /api/v1/journals/{pmid} [get]
 func (h *JournalHandler) GetJournal(c echo.Context) error {
 	tracer := otel.Tracer("http.handler.journal")
 	ctx, span := tracer.Start(c.Request().Context(), "GetJournalHandler")
 	defer span.End()
 
-	idParam := c.Param("id")
-	id, err := uuid.Parse(idParam)
+	pmid, err := strconv.ParseInt(c.Param("pmid"), 10, 64)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "invalid PMID")
+		return c.JSON(http.StatusBadRequest, domain.ResponseSingleData[domain.Empty]{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid journal PMID format",
+		})
+	}
+
+	fields, err := domain.ParseFields(c.QueryParam("fields"), domain.JournalFields)
 	if err != nil {
 		span.RecordError(err)
-		span.SetStatus(codes.Error, "invalid UUID")
+		span.SetStatus(codes.Error, "invalid fields")
 		return c.JSON(http.StatusBadRequest, domain.ResponseSingleData[domain.Empty]{
 			Code:    http.StatusBadRequest,
-			Message: "Invalid journal ID format",
+			Message: err.Error(),
 		})
 	}
 
-	span.SetAttributes(attribute.String("journal.id", id.String()))
-	j, err := h.Service.GetJournal(ctx, id)
+	span.SetAttributes(attribute.Int64("journal.pmid", pmid))
+	j, err := h.Service.GetJournal(ctx, pmid, fields)
+	if err == nil && j == nil {
+		err = domain.ErrNotFound
+	}
 	if err != nil {
 		span.RecordError(err)
-		if errors.Is(err, sql.ErrNoRows) {
+		if errors.Is(err, domain.ErrNotFound) {
 			span.SetStatus(codes.Error, "not found")
 			return c.JSON(http.StatusNotFound, domain.ResponseSingleData[domain.Empty]{
 				Code:    http.StatusNotFound,
@@ -128,9 +300,163 @@ func (h *JournalHandler) GetJournal(c echo.Context) error {
 		})
 	}
 
+	if fields != nil {
+		picked, err := domain.PickFields([]domain.Journal{*j}, fields)
+		if err != nil {
+			span.RecordError(err)
+			logging.LogError(ctx, err, "get_journal")
+			return c.JSON(http.StatusInternalServerError, domain.ResponseSingleData[domain.Empty]{
+				Code:    http.StatusInternalServerError,
+				Message: "Failed to get journal: " + err.Error(),
+			})
+		}
+		return c.JSON(http.StatusOK, domain.ResponseSingleData[map[string]json.RawMessage]{
+			Data:    picked[0],
+			Code:    http.StatusOK,
+			Message: "Successfully retrieved journal",
+		})
+	}
+
 	return c.JSON(http.StatusOK, domain.ResponseSingleData[domain.Journal]{
 		Data:    *j,
 		Code:    http.StatusOK,
 		Message: "Successfully retrieved journal",
 	})
 }
+
+// @Summary        Batch Get Journals
+// @Description    Resolve many journals by PMID at once, reporting the missing ones
+// @Tags           Journals
+// @Accept         json
+// @Produce        json
+// @Param          request body        domain.JournalBatchGetRequest true "PMIDs to resolve"
+// @Success        200     {object}    domain.ResponseSingleData[domain.JournalBatchGetResponse] "Successfully retrieved journals"
+// @Failure        400     {object}    domain.ResponseSingleData[domain.Empty]              "Bad request"
+// @Failure        500     {object}    domain.ResponseSingleData[domain.Empty]              "Internal server error"
+// @Router         /api/v1/journals:batchGet [post]
+func (h *JournalHandler) BatchGetJournals(c echo.Context) error {
+	tracer := otel.Tracer("http.handler.journal")
+	ctx, span := tracer.Start(c.Request().Context(), "BatchGetJournalsHandler")
+	defer span.End()
+
+	req := new(domain.JournalBatchGetRequest)
+	if err := c.Bind(req); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "invalid body")
+		return c.JSON(http.StatusBadRequest, domain.ResponseSingleData[domain.Empty]{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid request body",
+		})
+	}
+
+	span.SetAttributes(attribute.Int("journal.pmid_count", len(req.PMIDs)))
+	resp, err := h.Service.BatchGetJournals(ctx, req)
+	if err != nil {
+		span.RecordError(err)
+		if errors.Is(err, domain.ErrBadParamInput) {
+			span.SetStatus(codes.Error, "bad request")
+			return c.JSON(http.StatusBadRequest, domain.ResponseSingleData[domain.Empty]{
+				Code:    http.StatusBadRequest,
+				Message: err.Error(),
+			})
+		}
+
+		span.SetStatus(codes.Error, "service error")
+		logging.LogError(ctx, err, "batch_get_journals")
+		return c.JSON(http.StatusInternalServerError, domain.ResponseSingleData[domain.Empty]{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to get journals: " + err.Error(),
+		})
+	}
+
+	return c.JSON(http.StatusOK, domain.ResponseSingleData[domain.JournalBatchGetResponse]{
+		Data:    *resp,
+		Code:    http.StatusOK,
+		Message: "Successfully retrieved journals",
+	})
+}
+
+// @Summary        Get Similar Journals
+// @Description    Get the nearest neighbours of a journal using its stored embedding
+// @Tags           Journals
+// @Accept         json
+// @Produce        json
+// @Param          pmid    path        int true "Journal PMID"
+// @Param          filter  query       domain.SimilarJournalFilter  false "Similarity filters"
+// @Success        200     {object}    domain.ResponseMultipleData[domain.JournalResponse] "Successfully retrieved similar journals"
+// @Failure        400     {object}    domain.ResponseMultipleData[domain.Empty]              "Bad request"
+// @Failure        404     {object}    domain.ResponseMultipleData[domain.Empty]              "Journal embedding not found"
+// @Failure        500     {object}    domain.ResponseMultipleData[domain.Empty]              "Internal server error"
+// @Router         /api/v1/journals/{pmid}/similar [get]
+func (h *JournalHandler) GetSimilarJournals(c echo.Context) error {
+	tracer := otel.Tracer("http.handler.journal")
+	ctx, span := tracer.Start(c.Request().Context(), "GetSimilarJournalsHandler")
+	defer span.End()
+
+	pmid, err := strconv.ParseInt(c.Param("pmid"), 10, 64)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "invalid PMID")
+		return c.JSON(http.StatusBadRequest, domain.ResponseMultipleData[domain.Empty]{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid journal PMID format",
+		})
+	}
+
+	filter := new(domain.SimilarJournalFilter)
+	if err := c.Bind(filter); err != nil {
+		logging.LogWarn(ctx, "Failed to bind similar journal filter", slog.String("error", err.Error()))
+	}
+
+	span.SetAttributes(attribute.Int64("journal.pmid", pmid))
+	journals, err := h.Service.GetSimilarJournals(ctx, pmid, filter)
+	if err != nil {
+		span.RecordError(err)
+		if errors.Is(err, domain.ErrBadParamInput) {
+			span.SetStatus(codes.Error, "bad request")
+			return c.JSON(http.StatusBadRequest, domain.ResponseMultipleData[domain.Empty]{
+				Code:    http.StatusBadRequest,
+				Message: err.Error(),
+			})
+		}
+		if errors.Is(err, domain.ErrNotFound) {
+			span.SetStatus(codes.Error, "not found")
+			return c.JSON(http.StatusNotFound, domain.ResponseMultipleData[domain.Empty]{
+				Code:    http.StatusNotFound,
+				Message: "Journal embedding not found",
+			})
+		}
+
+		span.SetStatus(codes.Error, "service error")
+		logging.LogError(ctx, err, "get_similar_journals")
+		return c.JSON(http.StatusInternalServerError, domain.ResponseMultipleData[domain.Empty]{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to get similar journals: " + err.Error(),
+		})
+	}
+	if journals == nil {
+		journals = []domain.JournalResponse{}
+	}
+
+	return c.JSON(http.StatusOK, domain.ResponseMultipleData[domain.JournalResponse]{
+		Data:    journals,
+		Code:    http.StatusOK,
+		Message: "Successfully retrieve similar journals",
+	})
+}
+
+// searchErrorDetails returns where the search query is malformed when err is
+// a syntax error, so that clients can point at it.
+func searchErrorDetails(err error) []domain.ErrorDetail {
+	var syntaxErr *domain.SearchSyntaxError
+	if !errors.As(err, &syntaxErr) {
+		return nil
+	}
+
+	return []domain.ErrorDetail{{
+		Param:    "search",
+		Position: syntaxErr.Position,
+		Token:    syntaxErr.Token,
+		Message:  syntaxErr.Message,
+	}}
+}
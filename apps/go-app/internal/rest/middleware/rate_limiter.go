@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
 	"golang.org/x/time/rate"
 )
 
@@ -60,10 +61,24 @@ func (store *RateLimiterStore) getRateLimiter(ip string, r rate.Limit, b int) *r
 }
 
 func RateLimitMiddleware(requestsPerSecond float64, burstSize int) echo.MiddlewareFunc {
+	return RateLimitMiddlewareWithSkipper(requestsPerSecond, burstSize, middleware.DefaultSkipper)
+}
+
+// RateLimitMiddlewareWithSkipper is RateLimitMiddleware for the requests
+// skipper does not skip, e.g. routes with a limit of their own.
+func RateLimitMiddlewareWithSkipper(
+	requestsPerSecond float64,
+	burstSize int,
+	skipper middleware.Skipper,
+) echo.MiddlewareFunc {
 	store := NewRateLimiterStore()
 
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c echo.Context) error {
+			if skipper(c) {
+				return next(c)
+			}
+
 			ip := c.RealIP()
 			limiter := store.getRateLimiter(ip, rate.Limit(requestsPerSecond), burstSize)
 
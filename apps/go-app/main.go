@@ -59,7 +59,10 @@ func main() {
 		e.Use(middleware.SecurityHeadersMiddleware())
 	}
 	e.Use(middleware.CompressionMiddleware())
-	e.Use(middleware.RateLimitMiddleware(10.0, 20))
+	e.Use(middleware.RateLimitMiddlewareWithSkipper(10.0, 20, func(c echo.Context) bool {
+		// Typeahead is limited on its own, see rest.NewJournalHandler.
+		return c.Path() == "/api/v1/journals"+rest.SuggestPath
+	}))
 	e.Use(middleware.TimeoutMiddleware(180 * time.Second))
 
 	// Register the routes
@@ -73,6 +76,7 @@ func main() {
 	journalRepo := postgres.NewJournalRepository(dbPool)
 	embeddingHttp := httpRepo.NewEmbeddingHTTPRepository()
 	journalService := service.NewJournalService(journalRepo, embeddingHttp)
+	citationService := service.NewCitationService(postgres.NewJournalCitationRepository(dbPool))
 
 	// Swagger
 	enableSwagger := os.Getenv("ENABLE_SWAGGER")
@@ -88,6 +92,7 @@ func main() {
 	usersGroup := apiV1.Group("/journals")
 
 	rest.NewJournalHandler(usersGroup, journalService)
+	rest.NewCitationHandler(usersGroup, citationService)
 
 	// Get host from environment variable, default to 127.0.0.1 if not set
 	host := os.Getenv("APP_HOST")
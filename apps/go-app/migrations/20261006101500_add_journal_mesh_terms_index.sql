@@ -0,0 +1,9 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE INDEX journals_mesh_terms_idx ON journals USING gin (mesh_terms);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS journals_mesh_terms_idx;
+-- +goose StatementEnd
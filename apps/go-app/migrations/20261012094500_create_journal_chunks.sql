@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Passages of journals.content with one embedding column per model. Offsets
+-- are character offsets into the content, end exclusive.
+CREATE TABLE journal_chunks (
+    pmid BIGINT NOT NULL REFERENCES journals(pmid) ON DELETE CASCADE,
+    chunk_index INT NOT NULL,
+    content TEXT NOT NULL,
+    start_offset INT NOT NULL,
+    end_offset INT NOT NULL,
+    generalist_embeddings VECTOR(768) NOT NULL,
+    specialist_embeddings VECTOR(768) NOT NULL,
+    PRIMARY KEY (pmid, chunk_index)
+);
+CREATE INDEX ON journal_chunks USING hnsw (generalist_embeddings vector_cosine_ops);
+CREATE INDEX ON journal_chunks USING hnsw (specialist_embeddings vector_cosine_ops);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS journal_chunks;
+-- +goose StatementEnd
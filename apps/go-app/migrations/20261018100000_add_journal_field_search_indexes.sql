@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE INDEX journals_title_search_idx ON journals USING gin (to_tsvector('english', title));
+CREATE INDEX journals_abstract_search_idx ON journals USING gin (to_tsvector('english', abstract));
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS journals_abstract_search_idx;
+DROP INDEX IF EXISTS journals_title_search_idx;
+-- +goose StatementEnd
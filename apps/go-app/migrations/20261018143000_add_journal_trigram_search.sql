@@ -0,0 +1,26 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE INDEX journals_title_trgm_idx ON journals USING gin (title gin_trgm_ops);
+
+-- Title words and MeSH headings that misspelled searches are matched and
+-- corrected against. Refreshed by the vocabulary command.
+CREATE MATERIALIZED VIEW search_vocabulary AS
+    SELECT word AS term, 'title'::text AS source, ndoc::bigint AS frequency
+    FROM ts_stat('SELECT to_tsvector(''simple'', title) FROM journals')
+    WHERE length(word) >= 3
+    UNION ALL
+    SELECT term, 'mesh'::text AS source, count(*) AS frequency
+    FROM journals, unnest(mesh_terms) term
+    GROUP BY term;
+
+CREATE UNIQUE INDEX search_vocabulary_term_idx ON search_vocabulary (source, term);
+CREATE INDEX search_vocabulary_trgm_idx ON search_vocabulary USING gist (term gist_trgm_ops);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP MATERIALIZED VIEW IF EXISTS search_vocabulary;
+DROP INDEX IF EXISTS journals_title_trgm_idx;
+-- +goose StatementEnd
@@ -0,0 +1,12 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Byte ordered, so that they serve prefix LIKE patterns as well as ordering.
+CREATE INDEX journals_title_prefix_idx ON journals ((lower(title) COLLATE "C"));
+CREATE INDEX search_vocabulary_prefix_idx ON search_vocabulary (source, (lower(term) COLLATE "C"));
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS search_vocabulary_prefix_idx;
+DROP INDEX IF EXISTS journals_title_prefix_idx;
+-- +goose StatementEnd
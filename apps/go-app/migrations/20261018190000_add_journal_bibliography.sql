@@ -0,0 +1,47 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE journals
+    ADD COLUMN journal_name VARCHAR NOT NULL DEFAULT '',
+    ADD COLUMN publication_date DATE,
+    ADD COLUMN doi VARCHAR,
+    ADD COLUMN pmcid VARCHAR,
+    ADD COLUMN publication_types VARCHAR[] NOT NULL DEFAULT '{}',
+    ADD COLUMN language VARCHAR NOT NULL DEFAULT '';
+
+CREATE INDEX journals_publication_date_idx ON journals (publication_date);
+CREATE INDEX journals_journal_name_idx ON journals (lower(journal_name));
+CREATE INDEX journals_publication_types_idx ON journals USING gin (publication_types);
+CREATE INDEX journals_doi_idx ON journals (lower(doi));
+CREATE INDEX journals_pmcid_idx ON journals (pmcid);
+
+-- Authors in byline order. name is the display name, or the name of a
+-- collective author, which has no last and fore names.
+CREATE TABLE journal_authors (
+    pmid BIGINT NOT NULL REFERENCES journals(pmid) ON DELETE CASCADE,
+    position INT NOT NULL,
+    name VARCHAR NOT NULL,
+    last_name VARCHAR NOT NULL DEFAULT '',
+    fore_name VARCHAR NOT NULL DEFAULT '',
+    affiliations VARCHAR[] NOT NULL DEFAULT '{}',
+    PRIMARY KEY (pmid, position)
+);
+CREATE INDEX journal_authors_name_idx ON journal_authors (lower(name));
+CREATE INDEX journal_authors_last_name_idx ON journal_authors (lower(last_name));
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS journal_authors;
+DROP INDEX IF EXISTS journals_pmcid_idx;
+DROP INDEX IF EXISTS journals_doi_idx;
+DROP INDEX IF EXISTS journals_publication_types_idx;
+DROP INDEX IF EXISTS journals_journal_name_idx;
+DROP INDEX IF EXISTS journals_publication_date_idx;
+ALTER TABLE journals
+    DROP COLUMN IF EXISTS language,
+    DROP COLUMN IF EXISTS publication_types,
+    DROP COLUMN IF EXISTS pmcid,
+    DROP COLUMN IF EXISTS doi,
+    DROP COLUMN IF EXISTS publication_date,
+    DROP COLUMN IF EXISTS journal_name;
+-- +goose StatementEnd
@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Numeric signals of journals, such as citation_count, which ranking profiles
+-- boost relevance by. Journals lacking a signal have no row for it.
+CREATE TABLE journal_signals (
+    pmid BIGINT NOT NULL REFERENCES journals(pmid) ON DELETE CASCADE,
+    name VARCHAR NOT NULL,
+    value DOUBLE PRECISION NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (pmid, name)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS journal_signals;
+-- +goose StatementEnd
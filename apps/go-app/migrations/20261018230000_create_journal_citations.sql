@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+-- References of the journals of the corpus. The cited journal may be missing
+-- from the corpus, so it has no foreign key.
+CREATE TABLE journal_citations (
+    citing_pmid BIGINT NOT NULL REFERENCES journals(pmid) ON DELETE CASCADE,
+    cited_pmid BIGINT NOT NULL,
+    PRIMARY KEY (citing_pmid, cited_pmid),
+    CHECK (citing_pmid <> cited_pmid)
+);
+CREATE INDEX journal_citations_cited_pmid_idx ON journal_citations (cited_pmid, citing_pmid);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS journal_citations;
+-- +goose StatementEnd
@@ -0,0 +1,149 @@
+package service
+
+import (
+	"context"
+	"go-app/domain"
+	"go-app/internal/logging"
+	"unicode"
+
+	"github.com/pgvector/pgvector-go"
+	"go.opentelemetry.io/otel"
+)
+
+type JournalChunkRepository interface {
+	GetUnchunkedJournals(ctx context.Context, limit int) ([]domain.Journal, error)
+	ReplaceJournalChunks(ctx context.Context, pmid int64, chunks []domain.JournalChunk) error
+}
+
+type JournalChunkService struct {
+	r JournalChunkRepository
+	h EmbeddingHTTPRepository
+}
+
+func NewJournalChunkService(r JournalChunkRepository, h EmbeddingHTTPRepository) *JournalChunkService {
+	return &JournalChunkService{
+		r: r,
+		h: h,
+	}
+}
+
+// ChunkPendingJournals chunks and embeds up to batch journals that have no
+// chunks yet and returns how many were chunked, 0 once every journal is.
+func (s *JournalChunkService) ChunkPendingJournals(ctx context.Context, batch int) (int, error) {
+	tracer := otel.Tracer("service.journal_chunk")
+	ctxTrace, span := tracer.Start(ctx, "JournalChunkService.ChunkPendingJournals")
+	defer span.End()
+
+	journals, err := s.r.GetUnchunkedJournals(ctxTrace, batch)
+	if err != nil {
+		logging.LogError(ctx, err, "chunk_pending_journals_service")
+		return 0, err
+	}
+
+	for _, journal := range journals {
+		if err := s.ChunkJournal(ctxTrace, journal); err != nil {
+			logging.LogError(ctx, err, "chunk_pending_journals_service")
+			return 0, err
+		}
+	}
+
+	return len(journals), nil
+}
+
+// ChunkJournal splits the content of journal into passages, embeds every
+// passage with each model and replaces the stored chunks of the journal.
+func (s *JournalChunkService) ChunkJournal(ctx context.Context, journal domain.Journal) error {
+	chunks := chunkText(journal.Content, domain.MaxChunkRunes)
+	for i := range chunks {
+		chunks[i].PMID = journal.PMID
+		chunks[i].Embeddings = make(map[domain.VectorType]pgvector.Vector, len(domain.EnsembleVectorType.Models()))
+		for _, model := range domain.EnsembleVectorType.Models() {
+			embedding, err := s.h.GetGeneralEmbedding(ctx, chunks[i].Text, model)
+			if err != nil {
+				return err
+			}
+			chunks[i].Embeddings[model] = *embedding
+		}
+	}
+
+	return s.r.ReplaceJournalChunks(ctx, journal.PMID, chunks)
+}
+
+// chunkText splits text into chunks of at most maxRunes characters. Chunks are
+// made of whole paragraphs (separated by blank lines) where possible; longer
+// paragraphs are cut at the last sentence end, or else the last space, that
+// fits. Offsets are in characters and surrounding whitespace is left out.
+func chunkText(text string, maxRunes int) []domain.JournalChunk {
+	runes := []rune(text)
+
+	// Split into trimmed paragraph spans, then cut the ones that are too long.
+	type span struct{ start, end int }
+	var spans []span
+	addParagraph := func(start, end int) {
+		for start < end && unicode.IsSpace(runes[start]) {
+			start++
+		}
+		for end > start && unicode.IsSpace(runes[end-1]) {
+			end--
+		}
+		for end-start > maxRunes {
+			cut := cutPoint(runes, start, start+maxRunes)
+			spans = append(spans, span{start, cut})
+			start = cut
+			for start < end && unicode.IsSpace(runes[start]) {
+				start++
+			}
+		}
+		if end > start {
+			spans = append(spans, span{start, end})
+		}
+	}
+
+	paragraphStart, lineStart, blankLine := 0, 0, true
+	for i, r := range runes {
+		if r != '\n' {
+			blankLine = blankLine && unicode.IsSpace(r)
+			continue
+		}
+		if blankLine && lineStart > paragraphStart {
+			addParagraph(paragraphStart, lineStart)
+			paragraphStart = i + 1
+		}
+		lineStart, blankLine = i+1, true
+	}
+	addParagraph(paragraphStart, len(runes))
+
+	// Pack consecutive paragraphs into chunks.
+	var chunks []domain.JournalChunk
+	for i := 0; i < len(spans); {
+		start, end := spans[i].start, spans[i].end
+		for i++; i < len(spans) && spans[i].end-start <= maxRunes; i++ {
+			end = spans[i].end
+		}
+		chunks = append(chunks, domain.JournalChunk{
+			Index: len(chunks),
+			Text:  string(runes[start:end]),
+			Start: start,
+			End:   end,
+		})
+	}
+
+	return chunks
+}
+
+// cutPoint returns where to cut runes[start:limit], just after the last
+// sentence end or else at the last space of its second half, or at limit.
+func cutPoint(runes []rune, start, limit int) int {
+	half := start + (limit-start)/2
+	for i := limit - 1; i > half; i-- {
+		if unicode.IsSpace(runes[i]) && (runes[i-1] == '.' || runes[i-1] == '?' || runes[i-1] == '!') {
+			return i
+		}
+	}
+	for i := limit - 1; i > half; i-- {
+		if unicode.IsSpace(runes[i]) {
+			return i
+		}
+	}
+	return limit
+}
@@ -0,0 +1,98 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"go-app/domain"
+	"go-app/service"
+	"go-app/service/mocks"
+	"strings"
+	"testing"
+
+	"github.com/pgvector/pgvector-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestJournalChunkService_ChunkJournal(t *testing.T) {
+	mockChunkRepo := new(mocks.JournalChunkRepository)
+	mockEmbeddingHTTP := new(mocks.EmbeddingHTTPRepository)
+	chunkService := service.NewJournalChunkService(mockChunkRepo, mockEmbeddingHTTP)
+
+	ctx := context.Background()
+	embedding := pgvector.NewVector([]float32{0.1, 0.2})
+
+	t.Run("Packs short paragraphs into one chunk", func(t *testing.T) {
+		journal := domain.Journal{PMID: 7, Content: "\n  Background é.\n\nMethods.  \n"}
+		mockEmbeddingHTTP.On("GetGeneralEmbedding", mock.Anything, "Background é.\n\nMethods.", mock.Anything).
+			Return(&embedding, nil).Twice()
+		var saved []domain.JournalChunk
+		mockChunkRepo.On("ReplaceJournalChunks", mock.Anything, int64(7), mock.Anything).
+			Run(func(args mock.Arguments) { saved = args.Get(2).([]domain.JournalChunk) }).
+			Return(nil).Once()
+
+		err := chunkService.ChunkJournal(ctx, journal)
+
+		assert.NoError(t, err)
+		assert.Len(t, saved, 1)
+		assert.Equal(t, 3, saved[0].Start)
+		assert.Equal(t, 26, saved[0].End)
+		assert.Equal(t, saved[0].Text, string([]rune(journal.Content)[saved[0].Start:saved[0].End]))
+		assert.Len(t, saved[0].Embeddings, 2)
+
+		mockEmbeddingHTTP.AssertExpectations(t)
+		mockChunkRepo.AssertExpectations(t)
+	})
+
+	t.Run("Cuts long paragraphs at sentence ends", func(t *testing.T) {
+		sentence := strings.Repeat("word ", 99) + "end. "
+		journal := domain.Journal{PMID: 8, Content: strings.Repeat(sentence, 5)}
+		mockEmbeddingHTTP.On("GetGeneralEmbedding", mock.Anything, mock.Anything, mock.Anything).
+			Return(&embedding, nil)
+		var saved []domain.JournalChunk
+		mockChunkRepo.On("ReplaceJournalChunks", mock.Anything, int64(8), mock.Anything).
+			Run(func(args mock.Arguments) { saved = args.Get(2).([]domain.JournalChunk) }).
+			Return(nil).Once()
+
+		err := chunkService.ChunkJournal(ctx, journal)
+
+		assert.NoError(t, err)
+		assert.Len(t, saved, 3)
+		for i, c := range saved {
+			assert.Equal(t, i, c.Index)
+			assert.LessOrEqual(t, c.End-c.Start, domain.MaxChunkRunes)
+			assert.True(t, strings.HasSuffix(c.Text, "end."))
+			assert.Equal(t, c.Text, string([]rune(journal.Content)[c.Start:c.End]))
+		}
+	})
+}
+
+func TestJournalChunkService_ChunkPendingJournals(t *testing.T) {
+	mockChunkRepo := new(mocks.JournalChunkRepository)
+	mockEmbeddingHTTP := new(mocks.EmbeddingHTTPRepository)
+	chunkService := service.NewJournalChunkService(mockChunkRepo, mockEmbeddingHTTP)
+
+	ctx := context.Background()
+
+	t.Run("Reports when no journal is left", func(t *testing.T) {
+		mockChunkRepo.On("GetUnchunkedJournals", mock.Anything, 50).Return([]domain.Journal{}, nil).Once()
+
+		n, err := chunkService.ChunkPendingJournals(ctx, 50)
+
+		assert.NoError(t, err)
+		assert.Zero(t, n)
+	})
+
+	t.Run("Stops on embedding errors", func(t *testing.T) {
+		mockChunkRepo.On("GetUnchunkedJournals", mock.Anything, 50).
+			Return([]domain.Journal{{PMID: 1, Content: "Results."}}, nil).Once()
+		mockEmbeddingHTTP.On("GetGeneralEmbedding", mock.Anything, "Results.", mock.Anything).
+			Return(nil, errors.New("ai service unavailable")).Once()
+
+		n, err := chunkService.ChunkPendingJournals(ctx, 50)
+
+		assert.Error(t, err)
+		assert.Zero(t, n)
+		mockChunkRepo.AssertNotCalled(t, "ReplaceJournalChunks", mock.Anything, int64(1), mock.Anything)
+	})
+}
@@ -0,0 +1,152 @@
+package service
+
+import (
+	"context"
+	"go-app/domain"
+	"go-app/internal/logging"
+
+	"go.opentelemetry.io/otel"
+)
+
+type JournalCitationRepository interface {
+	GetCitationNeighbours(
+		ctx context.Context,
+		pmid int64,
+		relation domain.CitationRelation,
+		limit int,
+		offset int,
+	) ([]domain.CitationNeighbour, error)
+	HasCitationNode(ctx context.Context, pmid int64) (bool, error)
+	ImportCitations(ctx context.Context, citations []domain.JournalCitation) (int64, error)
+	GetCitationGraph(ctx context.Context) ([]int64, []domain.JournalCitation, error)
+	ReplaceJournalSignals(ctx context.Context, name string, signals []domain.JournalSignal) error
+}
+
+type CitationService struct {
+	r JournalCitationRepository
+}
+
+func NewCitationService(r JournalCitationRepository) *CitationService {
+	return &CitationService{
+		r: r,
+	}
+}
+
+// GetCitationNeighbours returns a page of the neighbours of the journal with
+// the given PMID by relation, or domain.ErrNotFound when the PMID is not a
+// node of the citation graph. One neighbour more than the page size is
+// fetched to tell whether a next page exists.
+func (s *CitationService) GetCitationNeighbours(
+	ctx context.Context,
+	pmid int64,
+	relation domain.CitationRelation,
+	filter *domain.CitationFilter,
+) (*domain.CitationList, error) {
+	tracer := otel.Tracer("service.citation")
+	ctxTrace, span := tracer.Start(ctx, "CitationService.GetCitationNeighbours")
+	defer span.End()
+
+	if err := filter.Validate(); err != nil {
+		return nil, err
+	}
+	limit, page := domain.DefaultCitationLimit, 0
+	if filter.Limit != nil {
+		limit = *filter.Limit
+	}
+	if filter.Page != nil {
+		page = *filter.Page
+	}
+
+	neighbours, err := s.r.GetCitationNeighbours(ctxTrace, pmid, relation, limit+1, page*limit)
+	if err != nil {
+		logging.LogError(ctx, err, "get_citation_neighbours_service")
+		return nil, err
+	}
+	if len(neighbours) == 0 && page == 0 {
+		// Tell an unknown journal from one without neighbours.
+		exists, err := s.r.HasCitationNode(ctxTrace, pmid)
+		if err != nil {
+			logging.LogError(ctx, err, "get_citation_neighbours_service")
+			return nil, err
+		}
+		if !exists {
+			return nil, domain.ErrNotFound
+		}
+	}
+
+	list := &domain.CitationList{Neighbours: neighbours}
+	list.Meta.Limit = limit
+	list.Meta.Page = &page
+	if len(neighbours) > limit {
+		list.Neighbours = neighbours[:limit]
+		list.Meta.HasNext = true
+	}
+
+	return list, nil
+}
+
+// ImportCitations stores citations and returns how many were new. Citations
+// of a journal to itself are dropped.
+func (s *CitationService) ImportCitations(ctx context.Context, citations []domain.JournalCitation) (int64, error) {
+	tracer := otel.Tracer("service.citation")
+	ctxTrace, span := tracer.Start(ctx, "CitationService.ImportCitations")
+	defer span.End()
+
+	kept := make([]domain.JournalCitation, 0, len(citations))
+	for _, c := range citations {
+		if c.CitingPMID != c.CitedPMID {
+			kept = append(kept, c)
+		}
+	}
+	if len(kept) == 0 {
+		return 0, nil
+	}
+
+	n, err := s.r.ImportCitations(ctxTrace, kept)
+	if err != nil {
+		logging.LogError(ctx, err, "import_citations_service")
+		return 0, err
+	}
+
+	return n, nil
+}
+
+// ComputeCitationSignals computes the signals of the journals of the corpus
+// from the citation graph and stores them: the number of journals citing each
+// as its domain.CitationCountSignal, and its PageRank among every node of the
+// graph, scaled by the number of nodes, as its domain.AuthoritySignal. It
+// returns the number of journals scored.
+func (s *CitationService) ComputeCitationSignals(ctx context.Context) (int, error) {
+	tracer := otel.Tracer("service.citation")
+	ctxTrace, span := tracer.Start(ctx, "CitationService.ComputeCitationSignals")
+	defer span.End()
+
+	pmids, citations, err := s.r.GetCitationGraph(ctxTrace)
+	if err != nil {
+		logging.LogError(ctx, err, "compute_citation_signals_service")
+		return 0, err
+	}
+
+	citedBy := make(map[int64]int, len(pmids))
+	for _, c := range citations {
+		citedBy[c.CitedPMID]++
+	}
+	ranks := pageRank(pmids, citations, domain.PageRankDamping)
+	counts := make([]domain.JournalSignal, len(pmids))
+	authority := make([]domain.JournalSignal, len(pmids))
+	for i, pmid := range pmids {
+		counts[i] = domain.JournalSignal{PMID: pmid, Name: domain.CitationCountSignal, Value: float64(citedBy[pmid])}
+		authority[i] = domain.JournalSignal{PMID: pmid, Name: domain.AuthoritySignal, Value: ranks[pmid] * float64(len(ranks))}
+	}
+
+	if err := s.r.ReplaceJournalSignals(ctxTrace, domain.CitationCountSignal, counts); err != nil {
+		logging.LogError(ctx, err, "compute_citation_signals_service")
+		return 0, err
+	}
+	if err := s.r.ReplaceJournalSignals(ctxTrace, domain.AuthoritySignal, authority); err != nil {
+		logging.LogError(ctx, err, "compute_citation_signals_service")
+		return 0, err
+	}
+
+	return len(pmids), nil
+}
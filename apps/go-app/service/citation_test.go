@@ -0,0 +1,150 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"go-app/domain"
+	"go-app/service"
+	"go-app/service/mocks"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCitationService_GetCitationNeighbours(t *testing.T) {
+	mockCitationRepo := new(mocks.JournalCitationRepository)
+	citationService := service.NewCitationService(mockCitationRepo)
+
+	ctx := context.Background()
+	limit, page := 2, 1
+
+	t.Run("Pages through the neighbours", func(t *testing.T) {
+		filter := &domain.CitationFilter{Limit: &limit, Page: &page}
+		mockCitationRepo.On("GetCitationNeighbours", mock.Anything, int64(7), domain.CitedByRelation, 3, 2).
+			Return([]domain.CitationNeighbour{{PMID: 1}, {PMID: 2}, {PMID: 3}}, nil).Once()
+
+		list, err := citationService.GetCitationNeighbours(ctx, 7, domain.CitedByRelation, filter)
+
+		assert.NoError(t, err)
+		assert.Len(t, list.Neighbours, 2)
+		assert.True(t, list.Meta.HasNext)
+		assert.Equal(t, 1, *list.Meta.Page)
+		mockCitationRepo.AssertNotCalled(t, "HasCitationNode", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Returns an empty page for a journal without neighbours", func(t *testing.T) {
+		mockCitationRepo.On("GetCitationNeighbours", mock.Anything, int64(8), domain.ReferencesRelation, 21, 0).
+			Return([]domain.CitationNeighbour{}, nil).Once()
+		mockCitationRepo.On("HasCitationNode", mock.Anything, int64(8)).Return(true, nil).Once()
+
+		list, err := citationService.GetCitationNeighbours(ctx, 8, domain.ReferencesRelation, &domain.CitationFilter{})
+
+		assert.NoError(t, err)
+		assert.Empty(t, list.Neighbours)
+		assert.False(t, list.Meta.HasNext)
+	})
+
+	t.Run("Returns not found for an unknown journal", func(t *testing.T) {
+		mockCitationRepo.On("GetCitationNeighbours", mock.Anything, int64(9), domain.CoCitedRelation, 21, 0).
+			Return(nil, nil).Once()
+		mockCitationRepo.On("HasCitationNode", mock.Anything, int64(9)).Return(false, nil).Once()
+
+		list, err := citationService.GetCitationNeighbours(ctx, 9, domain.CoCitedRelation, &domain.CitationFilter{})
+
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+		assert.Nil(t, list)
+	})
+
+	t.Run("Rejects a limit above the maximum", func(t *testing.T) {
+		tooMany := domain.MaxCitationLimit + 1
+
+		list, err := citationService.GetCitationNeighbours(ctx, 7, domain.CitedByRelation, &domain.CitationFilter{Limit: &tooMany})
+
+		assert.ErrorIs(t, err, domain.ErrBadParamInput)
+		assert.Nil(t, list)
+	})
+}
+
+func TestCitationService_ImportCitations(t *testing.T) {
+	mockCitationRepo := new(mocks.JournalCitationRepository)
+	citationService := service.NewCitationService(mockCitationRepo)
+
+	ctx := context.Background()
+
+	t.Run("Drops self-citations", func(t *testing.T) {
+		mockCitationRepo.On("ImportCitations", mock.Anything, []domain.JournalCitation{{CitingPMID: 1, CitedPMID: 2}}).
+			Return(int64(1), nil).Once()
+
+		n, err := citationService.ImportCitations(ctx, []domain.JournalCitation{
+			{CitingPMID: 1, CitedPMID: 2},
+			{CitingPMID: 3, CitedPMID: 3},
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), n)
+		mockCitationRepo.AssertExpectations(t)
+	})
+
+	t.Run("Returns error when repository fails", func(t *testing.T) {
+		mockCitationRepo.On("ImportCitations", mock.Anything, mock.Anything).
+			Return(int64(0), errors.New("db error")).Once()
+
+		_, err := citationService.ImportCitations(ctx, []domain.JournalCitation{{CitingPMID: 1, CitedPMID: 2}})
+
+		assert.Error(t, err)
+	})
+}
+
+func TestCitationService_ComputeCitationSignals(t *testing.T) {
+	mockCitationRepo := new(mocks.JournalCitationRepository)
+	citationService := service.NewCitationService(mockCitationRepo)
+
+	ctx := context.Background()
+
+	t.Run("Counts the citations and scores the journals of the corpus by PageRank", func(t *testing.T) {
+		// 1 and 3 cite 2, which cites 4, outside the corpus.
+		mockCitationRepo.On("GetCitationGraph", mock.Anything).Return(
+			[]int64{1, 2, 3},
+			[]domain.JournalCitation{{CitingPMID: 1, CitedPMID: 2}, {CitingPMID: 3, CitedPMID: 2}, {CitingPMID: 2, CitedPMID: 4}},
+			nil,
+		).Once()
+		saved := map[string][]domain.JournalSignal{}
+		save := func(args mock.Arguments) { saved[args.String(1)] = args.Get(2).([]domain.JournalSignal) }
+		mockCitationRepo.On("ReplaceJournalSignals", mock.Anything, domain.CitationCountSignal, mock.Anything).
+			Run(save).Return(nil).Once()
+		mockCitationRepo.On("ReplaceJournalSignals", mock.Anything, domain.AuthoritySignal, mock.Anything).
+			Run(save).Return(nil).Once()
+
+		n, err := citationService.ComputeCitationSignals(ctx)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 3, n)
+		assert.Equal(t, []domain.JournalSignal{
+			{PMID: 1, Name: domain.CitationCountSignal, Value: 0},
+			{PMID: 2, Name: domain.CitationCountSignal, Value: 2},
+			{PMID: 3, Name: domain.CitationCountSignal, Value: 0},
+		}, saved[domain.CitationCountSignal])
+		authority := map[int64]float64{}
+		for _, s := range saved[domain.AuthoritySignal] {
+			assert.Equal(t, domain.AuthoritySignal, s.Name)
+			authority[s.PMID] = s.Value
+		}
+		assert.Len(t, authority, 3)
+		assert.Equal(t, authority[1], authority[3])
+		assert.Greater(t, authority[2], authority[1])
+		// The scores of the four nodes average 1, so the corpus' is below 4.
+		assert.Less(t, authority[1]+authority[2]+authority[3], 4.0)
+
+		mockCitationRepo.AssertExpectations(t)
+	})
+
+	t.Run("Returns error when repository fails", func(t *testing.T) {
+		mockCitationRepo.On("GetCitationGraph", mock.Anything).Return(nil, nil, errors.New("db error")).Once()
+
+		n, err := citationService.ComputeCitationSignals(ctx)
+
+		assert.Error(t, err)
+		assert.Zero(t, n)
+	})
+}
@@ -4,8 +4,9 @@ import (
 	"context"
 	"go-app/domain"
 	"go-app/internal/logging"
+	"sort"
+	"time"
 
-	"github.com/google/uuid"
 	"github.com/pgvector/pgvector-go"
 	"go.opentelemetry.io/otel"
 )
@@ -14,9 +15,38 @@ type JournalRepository interface {
 	GetJournalList(
 		ctx context.Context,
 		filter *domain.JournalFilter,
-		embedding *pgvector.Vector,
+		embeddings domain.QueryEmbeddings,
+	) ([]domain.JournalResponse, error)
+	GetJournalCandidates(
+		ctx context.Context,
+		filter *domain.JournalFilter,
+		embeddings domain.QueryEmbeddings,
+	) ([]domain.JournalCandidate, error)
+	CountJournals(
+		ctx context.Context,
+		filter *domain.JournalFilter,
+		embeddings domain.QueryEmbeddings,
+		exact bool,
+	) (int64, error)
+	GetMeSHFacets(
+		ctx context.Context,
+		filter *domain.JournalFilter,
+		embeddings domain.QueryEmbeddings,
+		size int,
+		depth int,
+	) ([]domain.FacetCount, error)
+	GetHighlights(ctx context.Context, query string, pmids []int64) ([]domain.JournalHighlights, error)
+	GetJournalSignals(ctx context.Context, pmids []int64, names []string) ([]domain.JournalSignal, error)
+	ReplaceJournalSignals(ctx context.Context, name string, signals []domain.JournalSignal) error
+	GetSearchSuggestions(ctx context.Context, keywords string, size int) ([]string, error)
+	GetSuggestions(ctx context.Context, prefix string, limit int) ([]domain.Suggestion, error)
+	GetJournal(ctx context.Context, pmid int64, fields []string) (*domain.Journal, error)
+	GetJournalsByPMIDs(ctx context.Context, pmids []int64) ([]domain.Journal, error)
+	GetSimilarJournals(
+		ctx context.Context,
+		pmid int64,
+		filter *domain.SimilarJournalFilter,
 	) ([]domain.JournalResponse, error)
-	GetJournal(ctx context.Context, id uuid.UUID) (*domain.Journal, error)
 }
 
 type EmbeddingHTTPRepository interface {
@@ -25,6 +55,11 @@ type EmbeddingHTTPRepository interface {
 		sentence string,
 		vType domain.VectorType,
 	) (*pgvector.Vector, error)
+	GetRerankScores(
+		ctx context.Context,
+		query string,
+		documents []string,
+	) ([]float64, error)
 }
 
 type JournalService struct {
@@ -39,41 +74,517 @@ func NewJournalService(u JournalRepository, h EmbeddingHTTPRepository) *JournalS
 	}
 }
 
-// GetJournal fetches a user by ID.
+// GetJournal fetches a journal by PMID. Only the given fields are read, or
+// every field when fields is nil.
 func (s *JournalService) GetJournal(
 	ctx context.Context,
-	id uuid.UUID,
+	pmid int64,
+	fields []string,
 ) (*domain.Journal, error) {
 	tracer := otel.Tracer("service.journal")
 	ctxTrace, span := tracer.Start(ctx, "JournalService.GetJournal")
 	defer span.End()
 
-	user, err := s.r.GetJournal(ctxTrace, id)
+	journal, err := s.r.GetJournal(ctxTrace, pmid, fields)
+	if err != nil {
+		return nil, err
+	}
+	return journal, nil
+}
+
+// BatchGetJournals resolves a list of PMIDs at once. Found journals keep the
+// order of the request, and PMIDs without a journal are reported as missing.
+func (s *JournalService) BatchGetJournals(
+	ctx context.Context,
+	req *domain.JournalBatchGetRequest,
+) (*domain.JournalBatchGetResponse, error) {
+	tracer := otel.Tracer("service.journal")
+	ctxTrace, span := tracer.Start(ctx, "JournalService.BatchGetJournals")
+	defer span.End()
+
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	pmids := make([]int64, 0, len(req.PMIDs))
+	seen := make(map[int64]struct{}, len(req.PMIDs))
+	for _, pmid := range req.PMIDs {
+		if _, ok := seen[pmid]; ok {
+			continue
+		}
+		seen[pmid] = struct{}{}
+		pmids = append(pmids, pmid)
+	}
+
+	found, err := s.r.GetJournalsByPMIDs(ctxTrace, pmids)
 	if err != nil {
+		logging.LogError(ctx, err, "batch_get_journals_service")
 		return nil, err
 	}
-	return user, nil
+
+	byPMID := make(map[int64]domain.Journal, len(found))
+	for _, j := range found {
+		byPMID[j.PMID] = j
+	}
+
+	resp := &domain.JournalBatchGetResponse{
+		Journals: make([]domain.Journal, 0, len(found)),
+		Missing:  []int64{},
+	}
+	for _, pmid := range pmids {
+		if j, ok := byPMID[pmid]; ok {
+			resp.Journals = append(resp.Journals, j)
+		} else {
+			resp.Missing = append(resp.Missing, pmid)
+		}
+	}
+
+	return resp, nil
+}
+
+// embedQuery embeds the search query with every model of vType.
+func (s *JournalService) embedQuery(
+	ctx context.Context,
+	query string,
+	vType domain.VectorType,
+) (domain.QueryEmbeddings, error) {
+	embeddings := make(domain.QueryEmbeddings, len(vType.Models()))
+	for _, model := range vType.Models() {
+		embedding, err := s.h.GetGeneralEmbedding(ctx, query, model)
+		if err != nil {
+			return nil, err
+		}
+		embeddings[model] = *embedding
+	}
+
+	return embeddings, nil
 }
 
+// GetJournalList returns a page of journals matching filter. One journal more
+// than the page size is fetched to tell whether a next page exists, and the
+// total is only counted when filter.Count asks for it.
 func (s *JournalService) GetJournalList(
 	ctx context.Context,
 	filter *domain.JournalFilter,
-) ([]domain.JournalResponse, error) {
-	var embedding *pgvector.Vector
+) (*domain.JournalList, error) {
+	if err := filter.Validate(); err != nil {
+		return nil, err
+	}
+
+	var embeddings domain.QueryEmbeddings
 	var err error
 	if filter != nil && filter.VSearch != "" {
-		embedding, err = s.h.GetGeneralEmbedding(ctx, filter.VSearch, filter.Type)
+		embeddings, err = s.embedQuery(ctx, filter.VSearch, filter.Type)
 		if err != nil {
 			logging.LogError(ctx, err, "get_journal_list_service")
 			return nil, err
 		}
 	}
 
-	journals, err := s.r.GetJournalList(ctx, filter, embedding)
+	if filter != nil && (filter.Rerank || filter.IsBoosted()) {
+		return s.rescoreJournalList(ctx, filter, embeddings)
+	}
+	if filter != nil && filter.MMR {
+		return s.diversifyJournalList(ctx, filter, embeddings)
+	}
+
+	fetch := filter
+	if filter != nil && filter.Limit != nil {
+		overFetch := *filter
+		limit := *filter.Limit + 1
+		overFetch.Limit = &limit
+		fetch = &overFetch
+	}
+
+	journals, err := s.r.GetJournalList(ctx, fetch, embeddings)
+	if err != nil {
+		logging.LogError(ctx, err, "get_journal_list_service")
+		return nil, err
+	}
+
+	scoreType := filter.ScoreType()
+	for i := range journals {
+		journals[i].ScoreType = scoreType
+	}
+
+	list := &domain.JournalList{Journals: journals}
+	if filter == nil || filter.Limit == nil {
+		return list, nil
+	}
+
+	list.Meta.Limit = *filter.Limit
+	if filter.Cursor == "" {
+		list.Meta.Page = filter.Page
+	}
+	if len(journals) > *filter.Limit {
+		list.Journals = journals[:*filter.Limit]
+		list.Meta.HasNext = true
+		list.Meta.NextCursor = filter.NextCursor(list.Journals)
+	}
+
+	if err := s.completeJournalList(ctx, filter, embeddings, list); err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
+
+// rescoreJournalList fetches the top candidates of the first-stage search,
+// re-scores them with the cross-encoder of the AI service when filter.Rerank
+// is set and with the boosts of its ranking profile, and pages through them
+// in the new order. Re-ranking scores filter.RerankDepth candidates, and pages
+// past it are empty; boosts alone rank at least domain.DefaultBoostCandidates
+// candidates, always covering the requested page.
+func (s *JournalService) rescoreJournalList(
+	ctx context.Context,
+	filter *domain.JournalFilter,
+	embeddings domain.QueryEmbeddings,
+) (*domain.JournalList, error) {
+	tracer := otel.Tracer("service.journal")
+	ctxTrace, span := tracer.Start(ctx, "JournalService.rescoreJournalList")
+	defer span.End()
+
+	page := 0
+	if filter.Page != nil {
+		page = *filter.Page
+	}
+	depth := domain.DefaultRerankDepth
+	switch {
+	case filter.Rerank && filter.RerankDepth != nil:
+		depth = *filter.RerankDepth
+	case !filter.Rerank && filter.Limit != nil:
+		// One more journal than the page end tells whether a next page exists.
+		depth = max(domain.DefaultBoostCandidates, (page+1)**filter.Limit+1)
+	case !filter.Rerank:
+		depth = domain.DefaultBoostCandidates
+	}
+	candidates := *filter
+	firstPage := 0
+	candidates.Limit = &depth
+	candidates.Page = &firstPage
+	if candidates.Fields != "" {
+		// The cross-encoder reads the title and abstract of every candidate,
+		// and the recency boost its publication date.
+		candidates.Fields += ",title,abstract,publication_date"
+	}
+
+	journals, err := s.r.GetJournalList(ctxTrace, &candidates, embeddings)
 	if err != nil {
 		logging.LogError(ctx, err, "get_journal_list_service")
 		return nil, err
 	}
 
+	if filter.Rerank && len(journals) > 0 {
+		passages := make([]string, len(journals))
+		for i, j := range journals {
+			passages[i] = j.Title + ". " + j.Abstract
+			if j.Passage != nil {
+				passages[i] = j.Title + ". " + j.Passage.Text
+			}
+		}
+		scores, err := s.h.GetRerankScores(ctxTrace, filter.RerankQuery(), passages)
+		if err != nil {
+			logging.LogError(ctx, err, "get_journal_list_service")
+			return nil, err
+		}
+		for i := range journals {
+			journals[i].Distance = scores[i]
+		}
+	}
+
+	if settings := filter.RankingSettings(); settings.IsBoosted() && len(journals) > 0 {
+		relevance := make([]float64, len(journals))
+		pmids := make([]int64, len(journals))
+		for i, j := range journals {
+			// Lexical searches rank by their lexical score.
+			relevance[i] = j.Distance
+			if filter.VSearch == "" && !filter.Rerank {
+				relevance[i] = j.LexicalScore
+			}
+			pmids[i] = j.PMID
+		}
+
+		var signals []domain.JournalSignal
+		if names := boostedSignals(settings); len(names) > 0 {
+			signals, err = s.r.GetJournalSignals(ctxTrace, pmids, names)
+			if err != nil {
+				logging.LogError(ctx, err, "get_journal_list_service")
+				return nil, err
+			}
+		}
+		boostJournals(journals, relevance, signals, settings, time.Now())
+	}
+
+	scoreType := filter.ScoreType()
+	for i := range journals {
+		journals[i].ScoreType = scoreType
+	}
+	sort.SliceStable(journals, func(a, b int) bool {
+		return journals[a].Distance > journals[b].Distance
+	})
+
+	list := &domain.JournalList{Journals: journals}
+	if filter.Limit == nil {
+		return list, nil
+	}
+
+	start, end := pageWindow(len(journals), page, *filter.Limit)
+	list.Journals = journals[start:end]
+	list.Meta.Limit = *filter.Limit
+	list.Meta.Page = filter.Page
+	list.Meta.HasNext = end < len(journals)
+
+	if err := s.completeJournalList(ctx, filter, embeddings, list); err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
+
+// diversifyJournalList orders a pool of vector search candidates by maximal
+// marginal relevance, so that near-duplicates of earlier results sink. The
+// pool holds at least domain.DefaultMMRCandidates journals and always covers
+// the requested page.
+func (s *JournalService) diversifyJournalList(
+	ctx context.Context,
+	filter *domain.JournalFilter,
+	embeddings domain.QueryEmbeddings,
+) (*domain.JournalList, error) {
+	tracer := otel.Tracer("service.journal")
+	ctxTrace, span := tracer.Start(ctx, "JournalService.diversifyJournalList")
+	defer span.End()
+
+	limit, page := 10, 0
+	if filter.Limit != nil {
+		limit = *filter.Limit
+	}
+	if filter.Page != nil {
+		page = *filter.Page
+	}
+	lambda := domain.DefaultMMRLambda
+	if filter.Lambda != nil {
+		lambda = *filter.Lambda
+	}
+
+	// One more journal than the page end is picked to tell whether a next
+	// page exists.
+	end := (page + 1) * limit
+	pool := max(domain.DefaultMMRCandidates, end+1)
+	candidates := *filter
+	firstPage := 0
+	candidates.Limit = &pool
+	candidates.Page = &firstPage
+
+	pooled, err := s.r.GetJournalCandidates(ctxTrace, &candidates, embeddings)
+	if err != nil {
+		logging.LogError(ctx, err, "get_journal_list_service")
+		return nil, err
+	}
+
+	journals := maximalMarginalRelevance(pooled, lambda, end+1)
+	scoreType := filter.ScoreType()
+	for i := range journals {
+		journals[i].ScoreType = scoreType
+	}
+
+	start, end := pageWindow(len(journals), page, limit)
+	list := &domain.JournalList{Journals: journals[start:end]}
+	list.Meta.Limit = limit
+	list.Meta.Page = filter.Page
+	list.Meta.HasNext = end < len(journals)
+
+	if err := s.completeJournalList(ctx, filter, embeddings, list); err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
+
+// pageWindow returns the bounds of the page of the given size and index
+// within n ranked journals. JournalFilter.Validate keeps page and limit in
+// range, and pages past the journals are empty rather than out of bounds.
+func pageWindow(n, page, limit int) (start, end int) {
+	start = min(page*limit, n)
+	return start, min(start+limit, n)
+}
+
+// completeJournalList adds the highlights and the total of the journals of
+// list when filter asks for them, and "did you mean" suggestions when the
+// first page of a search is empty.
+func (s *JournalService) completeJournalList(
+	ctx context.Context,
+	filter *domain.JournalFilter,
+	embeddings domain.QueryEmbeddings,
+	list *domain.JournalList,
+) error {
+	isFirstPage := filter.Cursor == "" && (filter.Page == nil || *filter.Page == 0)
+	if keywords := filter.SearchKeywords(); len(list.Journals) == 0 && isFirstPage && keywords != "" {
+		suggestions, err := s.r.GetSearchSuggestions(ctx, keywords, domain.MaxSearchSuggestions)
+		if err != nil {
+			logging.LogError(ctx, err, "get_journal_list_service")
+			return err
+		}
+		list.Meta.Suggestions = suggestions
+	}
+
+	if filter.Highlight && len(list.Journals) > 0 {
+		pmids := make([]int64, len(list.Journals))
+		for i, j := range list.Journals {
+			pmids[i] = j.PMID
+		}
+		highlights, err := s.r.GetHighlights(ctx, filter.HighlightQuery(), pmids)
+		if err != nil {
+			logging.LogError(ctx, err, "get_journal_list_service")
+			return err
+		}
+		byPMID := make(map[int64]domain.JournalHighlights, len(highlights))
+		for _, h := range highlights {
+			byPMID[h.PMID] = h
+		}
+		for i := range list.Journals {
+			if h, ok := byPMID[list.Journals[i].PMID]; ok {
+				list.Journals[i].Highlights = &h
+			}
+		}
+	}
+
+	if filter.Count != domain.EstimatedCount && filter.Count != domain.ExactCount {
+		return nil
+	}
+
+	total, err := s.r.CountJournals(ctx, filter, embeddings, filter.Count == domain.ExactCount)
+	if err != nil {
+		logging.LogError(ctx, err, "get_journal_list_service")
+		return err
+	}
+	list.Meta.Total = &total
+	list.Meta.TotalEstimated = filter.Count == domain.EstimatedCount
+
+	return nil
+}
+
+// GetMeSHFacets returns the most frequent MeSH terms of the journals matched
+// by the filter, for a faceted search sidebar.
+func (s *JournalService) GetMeSHFacets(
+	ctx context.Context,
+	filter *domain.JournalFacetFilter,
+) ([]domain.FacetCount, error) {
+	tracer := otel.Tracer("service.journal")
+	ctxTrace, span := tracer.Start(ctx, "JournalService.GetMeSHFacets")
+	defer span.End()
+
+	if err := filter.Validate(); err != nil {
+		return nil, err
+	}
+
+	var embeddings domain.QueryEmbeddings
+	var err error
+	if filter.VSearch != "" {
+		embeddings, err = s.embedQuery(ctxTrace, filter.VSearch, filter.Type)
+		if err != nil {
+			logging.LogError(ctx, err, "get_mesh_facets_service")
+			return nil, err
+		}
+	}
+
+	size, depth := domain.DefaultFacetSize, domain.DefaultFacetDepth
+	if filter.Size != nil {
+		size = *filter.Size
+	}
+	if filter.Depth != nil {
+		depth = *filter.Depth
+	}
+
+	facets, err := s.r.GetMeSHFacets(ctxTrace, &filter.JournalFilter, embeddings, size, depth)
+	if err != nil {
+		logging.LogError(ctx, err, "get_mesh_facets_service")
+		return nil, err
+	}
+
+	return facets, nil
+}
+
+// SuggestJournals completes a typeahead prefix with journal titles and MeSH
+// headings. It only reads prefix indexes, so it is cheap enough to be called
+// on every keystroke.
+func (s *JournalService) SuggestJournals(
+	ctx context.Context,
+	filter *domain.SuggestFilter,
+) ([]domain.Suggestion, error) {
+	tracer := otel.Tracer("service.journal")
+	ctxTrace, span := tracer.Start(ctx, "JournalService.SuggestJournals")
+	defer span.End()
+
+	if err := filter.Validate(); err != nil {
+		return nil, err
+	}
+
+	limit := domain.DefaultSuggestLimit
+	if filter.Limit != nil {
+		limit = *filter.Limit
+	}
+
+	suggestions, err := s.r.GetSuggestions(ctxTrace, filter.Prefix, limit)
+	if err != nil {
+		logging.LogError(ctx, err, "suggest_journals_service")
+		return nil, err
+	}
+
+	return suggestions, nil
+}
+
+// ImportJournalSignals replaces the stored values of the signal with the
+// given name, such as domain.CitationCountSignal, by signals, for ranking
+// profiles to boost by.
+func (s *JournalService) ImportJournalSignals(
+	ctx context.Context,
+	name string,
+	signals []domain.JournalSignal,
+) error {
+	tracer := otel.Tracer("service.journal")
+	ctxTrace, span := tracer.Start(ctx, "JournalService.ImportJournalSignals")
+	defer span.End()
+
+	for _, signal := range signals {
+		if err := signal.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if err := s.r.ReplaceJournalSignals(ctxTrace, name, signals); err != nil {
+		logging.LogError(ctx, err, "import_journal_signals_service")
+		return err
+	}
+
+	return nil
+}
+
+// GetSimilarJournals finds the journals closest to an existing journal using
+// its stored embedding, so the AI service is never called.
+func (s *JournalService) GetSimilarJournals(
+	ctx context.Context,
+	pmid int64,
+	filter *domain.SimilarJournalFilter,
+) ([]domain.JournalResponse, error) {
+	tracer := otel.Tracer("service.journal")
+	ctxTrace, span := tracer.Start(ctx, "JournalService.GetSimilarJournals")
+	defer span.End()
+
+	if filter.Type == "" {
+		filter.Type = domain.GeneralVectorType
+	}
+	if err := filter.Validate(); err != nil {
+		return nil, err
+	}
+
+	journals, err := s.r.GetSimilarJournals(ctxTrace, pmid, filter)
+	if err != nil {
+		logging.LogError(ctx, err, "get_similar_journals_service")
+		return nil, err
+	}
+	for i := range journals {
+		journals[i].ScoreType = filter.Type.ScoreType()
+	}
+
 	return journals, nil
 }
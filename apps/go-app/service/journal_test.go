@@ -8,8 +8,9 @@ import (
 	"go-app/service/mocks"
 
 	"testing"
+	"time"
 
-	"github.com/google/uuid"
+	"github.com/pgvector/pgvector-go"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
@@ -20,15 +21,15 @@ func TestJournalService_GetJournal(t *testing.T) {
 	journalService := service.NewJournalService(mockJournalRepo, mockEmbeddingHTTP)
 
 	ctx := context.Background()
-	journalID := uuid.New()
+	journalPMID := int64(38012345)
 	expectedJournal := &domain.Journal{
 		Title: "Fetched Journal",
 	}
 
 	t.Run("Successfully fetches a journal", func(t *testing.T) {
-		mockJournalRepo.On("GetJournal", mock.Anything, journalID).Return(expectedJournal, nil).Once()
+		mockJournalRepo.On("GetJournal", mock.Anything, journalPMID, mock.Anything).Return(expectedJournal, nil).Once()
 
-		j, err := journalService.GetJournal(ctx, journalID)
+		j, err := journalService.GetJournal(ctx, journalPMID, nil)
 
 		assert.NoError(t, err)
 		assert.NotNil(t, j)
@@ -39,9 +40,9 @@ func TestJournalService_GetJournal(t *testing.T) {
 
 	t.Run("Returns error when repository fails", func(t *testing.T) {
 		repoErr := errors.New("network error")
-		mockJournalRepo.On("GetJournal", mock.Anything, journalID).Return(nil, repoErr).Once()
+		mockJournalRepo.On("GetJournal", mock.Anything, journalPMID, mock.Anything).Return(nil, repoErr).Once()
 
-		j, err := journalService.GetJournal(ctx, journalID)
+		j, err := journalService.GetJournal(ctx, journalPMID, nil)
 
 		assert.Error(t, err)
 		assert.Nil(t, j)
@@ -51,15 +52,69 @@ func TestJournalService_GetJournal(t *testing.T) {
 	})
 
 	t.Run("Returns nil when journal not found in repository", func(t *testing.T) {
-		mockJournalRepo.On("GetJournal", mock.Anything, journalID).Return(nil, nil).Once()
+		mockJournalRepo.On("GetJournal", mock.Anything, journalPMID, mock.Anything).Return(nil, nil).Once()
 
-		j, err := journalService.GetJournal(ctx, journalID)
+		j, err := journalService.GetJournal(ctx, journalPMID, nil)
 
 		assert.NoError(t, err)
 		assert.Nil(t, j)
 
 		mockJournalRepo.AssertExpectations(t)
 	})
+
+	t.Run("Passes the field selection to the repository", func(t *testing.T) {
+		fields := []string{"pmid", "title"}
+		mockJournalRepo.On("GetJournal", mock.Anything, journalPMID, fields).Return(expectedJournal, nil).Once()
+
+		j, err := journalService.GetJournal(ctx, journalPMID, fields)
+
+		assert.NoError(t, err)
+		assert.Equal(t, expectedJournal.Title, j.Title)
+
+		mockJournalRepo.AssertExpectations(t)
+	})
+}
+
+func TestJournalService_BatchGetJournals(t *testing.T) {
+	mockJournalRepo := new(mocks.JournalRepository)
+	mockEmbeddingHTTP := new(mocks.EmbeddingHTTPRepository)
+	journalService := service.NewJournalService(mockJournalRepo, mockEmbeddingHTTP)
+
+	ctx := context.Background()
+
+	t.Run("Keeps request order and reports missing PMIDs", func(t *testing.T) {
+		req := &domain.JournalBatchGetRequest{PMIDs: []int64{30, 10, 20, 10}}
+		mockJournalRepo.On("GetJournalsByPMIDs", mock.Anything, []int64{30, 10, 20}).
+			Return([]domain.Journal{{PMID: 10}, {PMID: 30}}, nil).Once()
+
+		resp, err := journalService.BatchGetJournals(ctx, req)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []int64{30, 10}, []int64{resp.Journals[0].PMID, resp.Journals[1].PMID})
+		assert.Equal(t, []int64{20}, resp.Missing)
+
+		mockJournalRepo.AssertExpectations(t)
+	})
+
+	t.Run("Rejects an empty request", func(t *testing.T) {
+		resp, err := journalService.BatchGetJournals(ctx, &domain.JournalBatchGetRequest{})
+
+		assert.ErrorIs(t, err, domain.ErrBadParamInput)
+		assert.Nil(t, resp)
+	})
+
+	t.Run("Returns error when repository fails", func(t *testing.T) {
+		repoErr := errors.New("batch get database error")
+		mockJournalRepo.On("GetJournalsByPMIDs", mock.Anything, []int64{1}).
+			Return(nil, repoErr).Once()
+
+		resp, err := journalService.BatchGetJournals(ctx, &domain.JournalBatchGetRequest{PMIDs: []int64{1}})
+
+		assert.Equal(t, repoErr, err)
+		assert.Nil(t, resp)
+
+		mockJournalRepo.AssertExpectations(t)
+	})
 }
 
 func TestJournalService_GetJournalList(t *testing.T) {
@@ -81,15 +136,15 @@ func TestJournalService_GetJournalList(t *testing.T) {
 			"GetJournalList",
 			mock.Anything,
 			filter,
-			mock.AnythingOfType("*pgvector.Vector"),
+			mock.AnythingOfType("domain.QueryEmbeddings"),
 		).Return(expectedJournals, nil).Once()
 
-		journals, err := journalService.GetJournalList(ctx, filter)
+		list, err := journalService.GetJournalList(ctx, filter)
 
 		assert.NoError(t, err)
-		assert.NotNil(t, journals)
-		assert.Len(t, journals, 2)
-		assert.Equal(t, expectedJournals[0].Title, journals[0].Title)
+		assert.NotNil(t, list)
+		assert.Len(t, list.Journals, 2)
+		assert.Equal(t, expectedJournals[0].Title, list.Journals[0].Title)
 
 		mockJournalRepo.AssertExpectations(t)
 	})
@@ -99,14 +154,14 @@ func TestJournalService_GetJournalList(t *testing.T) {
 			"GetJournalList",
 			mock.Anything,
 			filter,
-			mock.AnythingOfType("*pgvector.Vector"),
+			mock.AnythingOfType("domain.QueryEmbeddings"),
 		).Return([]domain.JournalResponse{}, nil).Once()
 
-		journals, err := journalService.GetJournalList(ctx, filter)
+		list, err := journalService.GetJournalList(ctx, filter)
 
 		assert.NoError(t, err)
-		assert.NotNil(t, journals)
-		assert.Len(t, journals, 0)
+		assert.NotNil(t, list)
+		assert.Len(t, list.Journals, 0)
 
 		mockJournalRepo.AssertExpectations(t)
 	})
@@ -117,15 +172,933 @@ func TestJournalService_GetJournalList(t *testing.T) {
 			"GetJournalList",
 			mock.Anything,
 			filter,
-			mock.AnythingOfType("*pgvector.Vector"),
+			mock.AnythingOfType("domain.QueryEmbeddings"),
 		).Return(nil, repoErr).Once()
 
-		journals, err := journalService.GetJournalList(ctx, filter)
+		list, err := journalService.GetJournalList(ctx, filter)
 
 		assert.Error(t, err)
-		assert.Nil(t, journals)
+		assert.Nil(t, list)
 		assert.Equal(t, repoErr, err)
 
 		mockJournalRepo.AssertExpectations(t)
 	})
+
+	t.Run("Rejects an out of range limit or page", func(t *testing.T) {
+		negative, zero, tooMany := -1, 0, domain.MaxJournalLimit+1
+		filters := []*domain.JournalFilter{
+			{Search: "test", Limit: &negative},
+			{Search: "test", Limit: &zero},
+			{Search: "test", Limit: &tooMany},
+			{Search: "test", Page: &negative},
+		}
+
+		for _, filter := range filters {
+			list, err := journalService.GetJournalList(ctx, filter)
+
+			assert.ErrorIs(t, err, domain.ErrBadParamInput)
+			assert.Nil(t, list)
+		}
+		mockJournalRepo.AssertExpectations(t)
+	})
+}
+
+func TestJournalService_GetJournalList_Hybrid(t *testing.T) {
+	mockJournalRepo := new(mocks.JournalRepository)
+	mockEmbeddingHTTP := new(mocks.EmbeddingHTTPRepository)
+	journalService := service.NewJournalService(mockJournalRepo, mockEmbeddingHTTP)
+
+	ctx := context.Background()
+	embedding := pgvector.NewVector([]float32{0.1, 0.2, 0.3})
+
+	t.Run("Embeds the vector query and passes it to the repository", func(t *testing.T) {
+		filter := &domain.JournalFilter{
+			Search:  "BRCA1",
+			VSearch: "breast cancer susceptibility gene",
+			Type:    domain.SpecialistVectorType,
+			Fusion:  domain.RRFFusion,
+		}
+		mockEmbeddingHTTP.On(
+			"GetGeneralEmbedding",
+			mock.Anything,
+			filter.VSearch,
+			domain.SpecialistVectorType,
+		).Return(&embedding, nil).Once()
+		embeddings := domain.QueryEmbeddings{domain.SpecialistVectorType: embedding}
+		mockJournalRepo.On("GetJournalList", mock.Anything, filter, embeddings).
+			Return([]domain.JournalResponse{{PMID: 1}}, nil).Once()
+
+		list, err := journalService.GetJournalList(ctx, filter)
+
+		assert.NoError(t, err)
+		assert.Len(t, list.Journals, 1)
+
+		mockEmbeddingHTTP.AssertExpectations(t)
+		mockJournalRepo.AssertExpectations(t)
+	})
+
+	t.Run("Rejects unknown fusion method", func(t *testing.T) {
+		filter := &domain.JournalFilter{
+			Search:  "BRCA1",
+			VSearch: "breast cancer",
+			Type:    domain.GeneralVectorType,
+			Fusion:  "borda",
+		}
+
+		list, err := journalService.GetJournalList(ctx, filter)
+
+		assert.ErrorIs(t, err, domain.ErrBadParamInput)
+		assert.Nil(t, list)
+	})
+}
+
+func TestJournalService_GetJournalList_Ensemble(t *testing.T) {
+	mockJournalRepo := new(mocks.JournalRepository)
+	mockEmbeddingHTTP := new(mocks.EmbeddingHTTPRepository)
+	journalService := service.NewJournalService(mockJournalRepo, mockEmbeddingHTTP)
+
+	ctx := context.Background()
+	generalist := pgvector.NewVector([]float32{0.1, 0.2})
+	specialist := pgvector.NewVector([]float32{0.3, 0.4})
+
+	t.Run("Embeds the query with every model", func(t *testing.T) {
+		weight := 2.0
+		filter := &domain.JournalFilter{
+			VSearch:          "tumour suppressor",
+			Type:             domain.EnsembleVectorType,
+			SpecialistWeight: &weight,
+		}
+		mockEmbeddingHTTP.On("GetGeneralEmbedding", mock.Anything, filter.VSearch, domain.GeneralVectorType).
+			Return(&generalist, nil).Once()
+		mockEmbeddingHTTP.On("GetGeneralEmbedding", mock.Anything, filter.VSearch, domain.SpecialistVectorType).
+			Return(&specialist, nil).Once()
+		embeddings := domain.QueryEmbeddings{
+			domain.GeneralVectorType:    generalist,
+			domain.SpecialistVectorType: specialist,
+		}
+		mockJournalRepo.On("GetJournalList", mock.Anything, filter, embeddings).
+			Return([]domain.JournalResponse{{PMID: 1, Distance: 0.03}}, nil).Once()
+
+		list, err := journalService.GetJournalList(ctx, filter)
+
+		assert.NoError(t, err)
+		assert.Equal(t, domain.RRFScore, list.Journals[0].ScoreType)
+		assert.Equal(t, 2.0, filter.ModelWeight(domain.SpecialistVectorType))
+		assert.Equal(t, 1.0, filter.ModelWeight(domain.GeneralVectorType))
+
+		mockEmbeddingHTTP.AssertExpectations(t)
+		mockJournalRepo.AssertExpectations(t)
+	})
+
+	t.Run("Rejects passages on an ensemble search", func(t *testing.T) {
+		filter := &domain.JournalFilter{VSearch: "tumour suppressor", Type: domain.EnsembleVectorType, Passages: true}
+
+		list, err := journalService.GetJournalList(ctx, filter)
+
+		assert.ErrorIs(t, err, domain.ErrBadParamInput)
+		assert.Nil(t, list)
+	})
+
+	t.Run("Rejects a negative model weight", func(t *testing.T) {
+		weight := -1.0
+		filter := &domain.JournalFilter{
+			VSearch:          "tumour suppressor",
+			Type:             domain.EnsembleVectorType,
+			GeneralistWeight: &weight,
+		}
+
+		list, err := journalService.GetJournalList(ctx, filter)
+
+		assert.ErrorIs(t, err, domain.ErrBadParamInput)
+		assert.Nil(t, list)
+	})
+}
+
+func TestJournalService_GetJournalList_Score(t *testing.T) {
+	mockJournalRepo := new(mocks.JournalRepository)
+	mockEmbeddingHTTP := new(mocks.EmbeddingHTTPRepository)
+	journalService := service.NewJournalService(mockJournalRepo, mockEmbeddingHTTP)
+
+	ctx := context.Background()
+
+	t.Run("Labels vector results with their score type", func(t *testing.T) {
+		minScore := 0.75
+		embedding := pgvector.NewVector([]float32{0.3, 0.1})
+		filter := &domain.JournalFilter{VSearch: "statin myopathy", Type: domain.GeneralVectorType, MinScore: &minScore}
+		mockEmbeddingHTTP.On("GetGeneralEmbedding", mock.Anything, filter.VSearch, filter.Type).
+			Return(&embedding, nil).Once()
+		mockJournalRepo.On("GetJournalList", mock.Anything, filter, domain.QueryEmbeddings{filter.Type: embedding}).
+			Return([]domain.JournalResponse{{PMID: 1, Distance: 0.82}}, nil).Once()
+
+		list, err := journalService.GetJournalList(ctx, filter)
+
+		assert.NoError(t, err)
+		assert.Equal(t, domain.CosineSimilarityScore, list.Journals[0].ScoreType)
+
+		mockEmbeddingHTTP.AssertExpectations(t)
+		mockJournalRepo.AssertExpectations(t)
+	})
+
+	t.Run("Rejects unknown recall profile", func(t *testing.T) {
+		filter := &domain.JournalFilter{VSearch: "statin myopathy", Type: domain.GeneralVectorType, Recall: "perfect"}
+
+		list, err := journalService.GetJournalList(ctx, filter)
+
+		assert.ErrorIs(t, err, domain.ErrBadParamInput)
+		assert.Nil(t, list)
+	})
+
+	t.Run("Rejects min_score on an unranked listing", func(t *testing.T) {
+		minScore := 0.5
+		filter := &domain.JournalFilter{MinScore: &minScore}
+
+		list, err := journalService.GetJournalList(ctx, filter)
+
+		assert.ErrorIs(t, err, domain.ErrBadParamInput)
+		assert.Nil(t, list)
+	})
+}
+
+func TestJournalService_GetJournalList_Cursor(t *testing.T) {
+	mockJournalRepo := new(mocks.JournalRepository)
+	mockEmbeddingHTTP := new(mocks.EmbeddingHTTPRepository)
+	journalService := service.NewJournalService(mockJournalRepo, mockEmbeddingHTTP)
+
+	ctx := context.Background()
+	limit := 2
+
+	t.Run("Next cursor resumes after the last journal of the page", func(t *testing.T) {
+		filter := &domain.JournalFilter{Limit: &limit, Search: "aspirin"}
+		expected := []domain.JournalResponse{
+			{PMID: 7, LexicalScore: 0.9},
+			{PMID: 3, LexicalScore: 0.4},
+			{PMID: 5, LexicalScore: 0.2},
+		}
+		mockJournalRepo.On(
+			"GetJournalList",
+			mock.Anything,
+			mock.MatchedBy(func(f *domain.JournalFilter) bool { return *f.Limit == limit+1 }),
+			mock.AnythingOfType("domain.QueryEmbeddings"),
+		).Return(expected, nil).Once()
+
+		list, err := journalService.GetJournalList(ctx, filter)
+		assert.NoError(t, err)
+		assert.Len(t, list.Journals, 2)
+		assert.True(t, list.Meta.HasNext)
+
+		next := &domain.JournalFilter{Limit: &limit, Search: "aspirin", Cursor: list.Meta.NextCursor}
+		after, err := next.After()
+		assert.NoError(t, err)
+		assert.Equal(t, int64(3), after.PMID)
+		assert.Equal(t, 0.4, *after.Score)
+
+		mockJournalRepo.AssertExpectations(t)
+	})
+
+	t.Run("Last page has no next cursor", func(t *testing.T) {
+		filter := &domain.JournalFilter{Limit: &limit}
+		mockJournalRepo.On("GetJournalList", mock.Anything, mock.Anything, mock.AnythingOfType("domain.QueryEmbeddings")).
+			Return([]domain.JournalResponse{{PMID: 1}, {PMID: 2}}, nil).Once()
+
+		list, err := journalService.GetJournalList(ctx, filter)
+
+		assert.NoError(t, err)
+		assert.Len(t, list.Journals, 2)
+		assert.False(t, list.Meta.HasNext)
+		assert.Empty(t, list.Meta.NextCursor)
+		assert.Nil(t, list.Meta.Total)
+
+		mockJournalRepo.AssertExpectations(t)
+	})
+
+	t.Run("No next cursor on a short page", func(t *testing.T) {
+		filter := &domain.JournalFilter{Limit: &limit}
+
+		assert.Empty(t, filter.NextCursor([]domain.JournalResponse{{PMID: 1}}))
+	})
+
+	t.Run("Rejects a cursor from an unranked listing on a ranked search", func(t *testing.T) {
+		unranked := &domain.JournalFilter{Limit: &limit}
+		cursor := unranked.NextCursor([]domain.JournalResponse{{PMID: 1}, {PMID: 2}})
+		filter := &domain.JournalFilter{Limit: &limit, Search: "aspirin", Cursor: cursor}
+
+		list, err := journalService.GetJournalList(ctx, filter)
+
+		assert.ErrorIs(t, err, domain.ErrBadParamInput)
+		assert.Nil(t, list)
+	})
+
+	t.Run("Rejects a malformed cursor", func(t *testing.T) {
+		filter := &domain.JournalFilter{Limit: &limit, Cursor: "not a cursor!"}
+
+		list, err := journalService.GetJournalList(ctx, filter)
+
+		assert.ErrorIs(t, err, domain.ErrBadParamInput)
+		assert.Nil(t, list)
+	})
+
+	t.Run("Rejects a cursor on fused and passage searches", func(t *testing.T) {
+		lexical := &domain.JournalFilter{Limit: &limit, Search: "aspirin"}
+		cursor := lexical.NextCursor([]domain.JournalResponse{{PMID: 1, LexicalScore: 0.9}, {PMID: 2, LexicalScore: 0.4}})
+		filters := []*domain.JournalFilter{
+			{Limit: &limit, Search: "aspirin", VSearch: "aspirin", Fusion: domain.RRFFusion, Cursor: cursor},
+			{Limit: &limit, VSearch: "aspirin", Passages: true, Cursor: cursor},
+		}
+
+		for _, filter := range filters {
+			assert.Empty(t, filter.NextCursor([]domain.JournalResponse{{PMID: 1}, {PMID: 2}}))
+
+			list, err := journalService.GetJournalList(ctx, filter)
+
+			assert.ErrorIs(t, err, domain.ErrBadParamInput)
+			assert.Nil(t, list)
+		}
+		mockJournalRepo.AssertExpectations(t)
+	})
+}
+
+func TestJournalService_GetJournalList_Count(t *testing.T) {
+	mockJournalRepo := new(mocks.JournalRepository)
+	mockEmbeddingHTTP := new(mocks.EmbeddingHTTPRepository)
+	journalService := service.NewJournalService(mockJournalRepo, mockEmbeddingHTTP)
+
+	ctx := context.Background()
+	limit, page := 10, 0
+
+	t.Run("Estimates the total when asked to", func(t *testing.T) {
+		filter := &domain.JournalFilter{Limit: &limit, Page: &page, Search: "sepsis", Count: domain.EstimatedCount}
+		mockJournalRepo.On("GetJournalList", mock.Anything, mock.Anything, mock.AnythingOfType("domain.QueryEmbeddings")).
+			Return([]domain.JournalResponse{{PMID: 1}}, nil).Once()
+		mockJournalRepo.On("CountJournals", mock.Anything, filter, mock.AnythingOfType("domain.QueryEmbeddings"), false).
+			Return(int64(4200), nil).Once()
+
+		list, err := journalService.GetJournalList(ctx, filter)
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(4200), *list.Meta.Total)
+		assert.True(t, list.Meta.TotalEstimated)
+		assert.Equal(t, &page, list.Meta.Page)
+		assert.Equal(t, limit, list.Meta.Limit)
+
+		mockJournalRepo.AssertExpectations(t)
+	})
+
+	t.Run("Rejects unknown count mode", func(t *testing.T) {
+		filter := &domain.JournalFilter{Limit: &limit, Count: "approximate"}
+
+		list, err := journalService.GetJournalList(ctx, filter)
+
+		assert.ErrorIs(t, err, domain.ErrBadParamInput)
+		assert.Nil(t, list)
+	})
+}
+
+func TestJournalService_GetJournalList_Rerank(t *testing.T) {
+	mockJournalRepo := new(mocks.JournalRepository)
+	mockEmbeddingHTTP := new(mocks.EmbeddingHTTPRepository)
+	journalService := service.NewJournalService(mockJournalRepo, mockEmbeddingHTTP)
+
+	ctx := context.Background()
+	limit, page, depth := 2, 0, 3
+
+	t.Run("Reorders the candidates by cross-encoder score", func(t *testing.T) {
+		filter := &domain.JournalFilter{Limit: &limit, Page: &page, Search: "sepsis", Rerank: true, RerankDepth: &depth}
+		candidates := []domain.JournalResponse{
+			{PMID: 1, Title: "A", Abstract: "a", LexicalScore: 0.9},
+			{PMID: 2, Title: "B", Abstract: "b", LexicalScore: 0.5},
+			{PMID: 3, Title: "C", Abstract: "c", LexicalScore: 0.1},
+		}
+		mockJournalRepo.On(
+			"GetJournalList",
+			mock.Anything,
+			mock.MatchedBy(func(f *domain.JournalFilter) bool { return *f.Limit == depth && *f.Page == 0 }),
+			mock.AnythingOfType("domain.QueryEmbeddings"),
+		).Return(candidates, nil).Once()
+		mockEmbeddingHTTP.On("GetRerankScores", mock.Anything, "sepsis", []string{"A. a", "B. b", "C. c"}).
+			Return([]float64{-1.5, 2.0, 0.3}, nil).Once()
+
+		list, err := journalService.GetJournalList(ctx, filter)
+
+		assert.NoError(t, err)
+		assert.Len(t, list.Journals, 2)
+		assert.Equal(t, int64(2), list.Journals[0].PMID)
+		assert.Equal(t, int64(3), list.Journals[1].PMID)
+		assert.Equal(t, 2.0, list.Journals[0].Distance)
+		assert.Equal(t, domain.CrossEncoderScore, list.Journals[0].ScoreType)
+		assert.True(t, list.Meta.HasNext)
+		assert.Empty(t, list.Meta.NextCursor)
+
+		mockJournalRepo.AssertExpectations(t)
+		mockEmbeddingHTTP.AssertExpectations(t)
+	})
+
+	t.Run("Returns the re-ranking error", func(t *testing.T) {
+		filter := &domain.JournalFilter{Limit: &limit, Page: &page, Search: "sepsis", Rerank: true}
+		mockJournalRepo.On("GetJournalList", mock.Anything, mock.Anything, mock.AnythingOfType("domain.QueryEmbeddings")).
+			Return([]domain.JournalResponse{{PMID: 1}}, nil).Once()
+		mockEmbeddingHTTP.On("GetRerankScores", mock.Anything, "sepsis", mock.Anything).
+			Return(nil, errors.New("ai service unavailable")).Once()
+
+		list, err := journalService.GetJournalList(ctx, filter)
+
+		assert.Error(t, err)
+		assert.Nil(t, list)
+	})
+
+	t.Run("Rejects rerank on an unranked listing", func(t *testing.T) {
+		filter := &domain.JournalFilter{Limit: &limit, Rerank: true}
+
+		list, err := journalService.GetJournalList(ctx, filter)
+
+		assert.ErrorIs(t, err, domain.ErrBadParamInput)
+		assert.Nil(t, list)
+	})
+
+	t.Run("Rejects rerank_depth above the maximum", func(t *testing.T) {
+		tooDeep := domain.MaxRerankDepth + 1
+		filter := &domain.JournalFilter{Limit: &limit, Search: "sepsis", Rerank: true, RerankDepth: &tooDeep}
+
+		list, err := journalService.GetJournalList(ctx, filter)
+
+		assert.ErrorIs(t, err, domain.ErrBadParamInput)
+		assert.Nil(t, list)
+	})
+
+	t.Run("Returns an empty page past the re-ranked candidates", func(t *testing.T) {
+		past := 5
+		filter := &domain.JournalFilter{Limit: &limit, Page: &past, Search: "sepsis", Rerank: true, RerankDepth: &depth}
+		mockJournalRepo.On("GetJournalList", mock.Anything, mock.Anything, mock.AnythingOfType("domain.QueryEmbeddings")).
+			Return([]domain.JournalResponse{{PMID: 1, Title: "A"}, {PMID: 2, Title: "B"}}, nil).Once()
+		mockEmbeddingHTTP.On("GetRerankScores", mock.Anything, "sepsis", mock.Anything).
+			Return([]float64{0.1, 0.2}, nil).Once()
+
+		list, err := journalService.GetJournalList(ctx, filter)
+
+		assert.NoError(t, err)
+		assert.Empty(t, list.Journals)
+		assert.False(t, list.Meta.HasNext)
+
+		mockJournalRepo.AssertExpectations(t)
+		mockEmbeddingHTTP.AssertExpectations(t)
+	})
+
+	t.Run("Rejects a negative page", func(t *testing.T) {
+		negative := -1
+		filter := &domain.JournalFilter{Limit: &limit, Page: &negative, Search: "sepsis", Rerank: true}
+
+		list, err := journalService.GetJournalList(ctx, filter)
+
+		assert.ErrorIs(t, err, domain.ErrBadParamInput)
+		assert.Nil(t, list)
+	})
+}
+
+func TestJournalService_GetJournalList_Ranking(t *testing.T) {
+	mockJournalRepo := new(mocks.JournalRepository)
+	mockEmbeddingHTTP := new(mocks.EmbeddingHTTPRepository)
+	journalService := service.NewJournalService(mockJournalRepo, mockEmbeddingHTTP)
+
+	ctx := context.Background()
+	limit, page := 2, 0
+	lastYear := time.Now().AddDate(-1, 0, 0).Format(time.DateOnly)
+
+	t.Run("Boosts recent journals", func(t *testing.T) {
+		filter := &domain.JournalFilter{Limit: &limit, Page: &page, Search: "hypertension", Ranking: domain.RecentRanking}
+		old := domain.JournalResponse{PMID: 1, LexicalScore: 1.0}
+		old.PublicationDate = "1990-06-01"
+		recent := domain.JournalResponse{PMID: 2, LexicalScore: 0.95}
+		recent.PublicationDate = lastYear
+		unrelated := domain.JournalResponse{PMID: 3, LexicalScore: 0}
+		mockJournalRepo.On(
+			"GetJournalList",
+			mock.Anything,
+			mock.MatchedBy(func(f *domain.JournalFilter) bool { return *f.Limit == domain.DefaultBoostCandidates }),
+			mock.AnythingOfType("domain.QueryEmbeddings"),
+		).Return([]domain.JournalResponse{old, recent, unrelated}, nil).Once()
+
+		list, err := journalService.GetJournalList(ctx, filter)
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), list.Journals[0].PMID)
+		assert.Equal(t, int64(1), list.Journals[1].PMID)
+		assert.Equal(t, domain.BoostedScore, list.Journals[0].ScoreType)
+		assert.True(t, list.Meta.HasNext)
+
+		components := list.Journals[0].ScoreComponents
+		assert.Len(t, components, 2)
+		assert.Equal(t, domain.RelevanceComponent, components[0].Name)
+		assert.Equal(t, 0.95, components[0].Raw)
+		assert.InDelta(t, 0.95, components[0].Value, 1e-9)
+		assert.Equal(t, domain.RecencyComponent, components[1].Name)
+		assert.InDelta(t, 1, components[1].Raw, 0.01)
+		assert.InDelta(t, 0.87, components[1].Value, 0.01)
+		assert.InDelta(t, components[0].Value+0.5*components[1].Value, list.Journals[0].Distance, 1e-9)
+		mockJournalRepo.AssertNotCalled(t, "GetJournalSignals", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Boosts cited journals", func(t *testing.T) {
+		filter := &domain.JournalFilter{Limit: &limit, Page: &page, VSearch: "statins", Type: domain.GeneralVectorType, Ranking: domain.CitedRanking}
+		mockEmbeddingHTTP.On("GetGeneralEmbedding", mock.Anything, "statins", domain.GeneralVectorType).
+			Return(&pgvector.Vector{}, nil).Once()
+		mockJournalRepo.On("GetJournalList", mock.Anything, mock.Anything, mock.AnythingOfType("domain.QueryEmbeddings")).
+			Return([]domain.JournalResponse{{PMID: 1, Distance: 0.9}, {PMID: 2, Distance: 0.88}, {PMID: 3, Distance: 0.5}}, nil).Once()
+		mockJournalRepo.On("GetJournalSignals", mock.Anything, []int64{1, 2, 3}, []string{domain.CitationCountSignal}).
+			Return([]domain.JournalSignal{{PMID: 2, Name: domain.CitationCountSignal, Value: 450}}, nil).Once()
+
+		list, err := journalService.GetJournalList(ctx, filter)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []int64{2, 1}, []int64{list.Journals[0].PMID, list.Journals[1].PMID})
+		assert.True(t, list.Meta.HasNext)
+		assert.Equal(t, 450.0, list.Journals[0].ScoreComponents[1].Raw)
+		assert.InDelta(t, 0.9, list.Journals[0].ScoreComponents[1].Value, 1e-9)
+		assert.Equal(t, 0.0, list.Journals[1].ScoreComponents[1].Raw)
+		mockJournalRepo.AssertExpectations(t)
+	})
+
+	t.Run("Rejects invalid rankings", func(t *testing.T) {
+		negative, cursor := -1.0, domain.JournalCursor{PMID: 1}.Encode()
+		for _, filter := range []*domain.JournalFilter{
+			{Limit: &limit, Search: "hypertension", Ranking: "popular"},
+			{Limit: &limit, Search: "hypertension", Ranking: domain.RecentRanking, RecencyHalfLife: &negative},
+			{Limit: &limit, Search: "hypertension", CitationWeight: &negative},
+			{Limit: &limit, Ranking: domain.ClinicalRanking},
+			{Limit: &limit, Search: "hypertension", Ranking: domain.ClinicalRanking, Cursor: cursor},
+		} {
+			list, err := journalService.GetJournalList(ctx, filter)
+
+			assert.ErrorIs(t, err, domain.ErrBadParamInput, filter)
+			assert.Nil(t, list)
+		}
+	})
+}
+
+func TestJournalService_GetJournalList_MMR(t *testing.T) {
+	mockJournalRepo := new(mocks.JournalRepository)
+	mockEmbeddingHTTP := new(mocks.EmbeddingHTTPRepository)
+	journalService := service.NewJournalService(mockJournalRepo, mockEmbeddingHTTP)
+
+	ctx := context.Background()
+	limit, page := 2, 0
+	embedding := pgvector.NewVector([]float32{1, 0})
+
+	t.Run("Skips near-duplicates of earlier results", func(t *testing.T) {
+		lambda := 0.5
+		filter := &domain.JournalFilter{
+			Limit: &limit, Page: &page, VSearch: "statin review", Type: domain.GeneralVectorType, MMR: true, Lambda: &lambda,
+		}
+		mockEmbeddingHTTP.On("GetGeneralEmbedding", mock.Anything, filter.VSearch, filter.Type).
+			Return(&embedding, nil).Once()
+		mockJournalRepo.On(
+			"GetJournalCandidates",
+			mock.Anything,
+			mock.MatchedBy(func(f *domain.JournalFilter) bool {
+				return *f.Limit == domain.DefaultMMRCandidates && *f.Page == 0
+			}),
+			domain.QueryEmbeddings{filter.Type: embedding},
+		).Return([]domain.JournalCandidate{
+			{JournalResponse: domain.JournalResponse{PMID: 1, Distance: 0.9}, Embedding: pgvector.NewVector([]float32{1, 0})},
+			{JournalResponse: domain.JournalResponse{PMID: 2, Distance: 0.88}, Embedding: pgvector.NewVector([]float32{1, 0.01})},
+			{JournalResponse: domain.JournalResponse{PMID: 3, Distance: 0.5}, Embedding: pgvector.NewVector([]float32{0, 1})},
+		}, nil).Once()
+
+		list, err := journalService.GetJournalList(ctx, filter)
+
+		assert.NoError(t, err)
+		assert.Len(t, list.Journals, 2)
+		assert.Equal(t, int64(1), list.Journals[0].PMID)
+		assert.Equal(t, int64(3), list.Journals[1].PMID)
+		assert.Equal(t, domain.CosineSimilarityScore, list.Journals[1].ScoreType)
+		assert.True(t, list.Meta.HasNext)
+
+		mockEmbeddingHTTP.AssertExpectations(t)
+		mockJournalRepo.AssertExpectations(t)
+	})
+
+	t.Run("Rejects mmr without a vector search", func(t *testing.T) {
+		filter := &domain.JournalFilter{Limit: &limit, Search: "statin", MMR: true}
+
+		list, err := journalService.GetJournalList(ctx, filter)
+
+		assert.ErrorIs(t, err, domain.ErrBadParamInput)
+		assert.Nil(t, list)
+	})
+
+	t.Run("Rejects lambda outside [0, 1]", func(t *testing.T) {
+		lambda := 1.5
+		filter := &domain.JournalFilter{
+			Limit: &limit, VSearch: "statin review", Type: domain.GeneralVectorType, MMR: true, Lambda: &lambda,
+		}
+
+		list, err := journalService.GetJournalList(ctx, filter)
+
+		assert.ErrorIs(t, err, domain.ErrBadParamInput)
+		assert.Nil(t, list)
+	})
+
+	t.Run("Returns an empty page past the candidates", func(t *testing.T) {
+		past := 3
+		filter := &domain.JournalFilter{
+			Limit: &limit, Page: &past, VSearch: "statin review", Type: domain.GeneralVectorType, MMR: true,
+		}
+		mockEmbeddingHTTP.On("GetGeneralEmbedding", mock.Anything, filter.VSearch, filter.Type).
+			Return(&embedding, nil).Once()
+		mockJournalRepo.On("GetJournalCandidates", mock.Anything, mock.Anything, mock.Anything).
+			Return([]domain.JournalCandidate{
+				{JournalResponse: domain.JournalResponse{PMID: 1, Distance: 0.9}, Embedding: pgvector.NewVector([]float32{1, 0})},
+			}, nil).Once()
+
+		list, err := journalService.GetJournalList(ctx, filter)
+
+		assert.NoError(t, err)
+		assert.Empty(t, list.Journals)
+		assert.False(t, list.Meta.HasNext)
+
+		mockEmbeddingHTTP.AssertExpectations(t)
+		mockJournalRepo.AssertExpectations(t)
+	})
+
+	t.Run("Rejects a negative page", func(t *testing.T) {
+		negative := -1
+		filter := &domain.JournalFilter{
+			Limit: &limit, Page: &negative, VSearch: "statin review", Type: domain.GeneralVectorType, MMR: true,
+		}
+
+		list, err := journalService.GetJournalList(ctx, filter)
+
+		assert.ErrorIs(t, err, domain.ErrBadParamInput)
+		assert.Nil(t, list)
+	})
+}
+
+func TestJournalService_GetJournalList_Highlight(t *testing.T) {
+	mockJournalRepo := new(mocks.JournalRepository)
+	mockEmbeddingHTTP := new(mocks.EmbeddingHTTPRepository)
+	journalService := service.NewJournalService(mockJournalRepo, mockEmbeddingHTTP)
+
+	ctx := context.Background()
+	limit, page := 10, 0
+
+	t.Run("Attaches the highlights of the page", func(t *testing.T) {
+		filter := &domain.JournalFilter{Limit: &limit, Page: &page, Search: "sepsis", Highlight: true, OmitContent: true}
+		mockJournalRepo.On("GetJournalList", mock.Anything, mock.Anything, mock.AnythingOfType("domain.QueryEmbeddings")).
+			Return([]domain.JournalResponse{{PMID: 4}, {PMID: 9}}, nil).Once()
+		mockJournalRepo.On("GetHighlights", mock.Anything, "sepsis", []int64{4, 9}).
+			Return([]domain.JournalHighlights{{PMID: 9, Title: "<mark>Sepsis</mark> in neonates"}}, nil).Once()
+
+		list, err := journalService.GetJournalList(ctx, filter)
+
+		assert.NoError(t, err)
+		assert.Nil(t, list.Journals[0].Highlights)
+		assert.Equal(t, "<mark>Sepsis</mark> in neonates", list.Journals[1].Highlights.Title)
+
+		mockJournalRepo.AssertExpectations(t)
+	})
+
+	t.Run("Rejects highlight without a query", func(t *testing.T) {
+		filter := &domain.JournalFilter{Limit: &limit, Highlight: true}
+
+		list, err := journalService.GetJournalList(ctx, filter)
+
+		assert.ErrorIs(t, err, domain.ErrBadParamInput)
+		assert.Nil(t, list)
+	})
+}
+
+func TestJournalService_GetJournalList_Fields(t *testing.T) {
+	mockJournalRepo := new(mocks.JournalRepository)
+	mockEmbeddingHTTP := new(mocks.EmbeddingHTTPRepository)
+	journalService := service.NewJournalService(mockJournalRepo, mockEmbeddingHTTP)
+
+	ctx := context.Background()
+	limit := 10
+
+	t.Run("Selects the requested fields", func(t *testing.T) {
+		filter := &domain.JournalFilter{Limit: &limit, Search: "sepsis", Fields: "pmid, title,distance,title"}
+
+		assert.NoError(t, filter.Validate())
+		assert.Equal(t, []string{"pmid", "title", "distance"}, filter.SelectedFields())
+
+		picked, err := domain.PickFields([]domain.JournalResponse{{PMID: 3, Title: "Sepsis", Content: "Body"}}, filter.SelectedFields())
+		assert.NoError(t, err)
+		assert.Len(t, picked[0], 3)
+		assert.JSONEq(t, `"Sepsis"`, string(picked[0]["title"]))
+	})
+
+	t.Run("Leaves content out when omitted", func(t *testing.T) {
+		filter := &domain.JournalFilter{OmitContent: true}
+
+		assert.NotContains(t, filter.SelectedFields(), "content")
+		assert.Contains(t, filter.SelectedFields(), "title")
+	})
+
+	t.Run("Rejects unknown fields", func(t *testing.T) {
+		filter := &domain.JournalFilter{Limit: &limit, Fields: "pmid,password"}
+
+		list, err := journalService.GetJournalList(ctx, filter)
+
+		assert.ErrorIs(t, err, domain.ErrBadParamInput)
+		assert.Nil(t, list)
+	})
+}
+
+func TestJournalService_GetJournalList_BooleanSearch(t *testing.T) {
+	mockJournalRepo := new(mocks.JournalRepository)
+	mockEmbeddingHTTP := new(mocks.EmbeddingHTTPRepository)
+	journalService := service.NewJournalService(mockJournalRepo, mockEmbeddingHTTP)
+
+	ctx := context.Background()
+	limit := 10
+
+	t.Run("Rejects an invalid search", func(t *testing.T) {
+		filter := &domain.JournalFilter{Limit: &limit, Search: "sepsis AND (shock"}
+
+		list, err := journalService.GetJournalList(ctx, filter)
+
+		var syntaxErr *domain.SearchSyntaxError
+		assert.ErrorAs(t, err, &syntaxErr)
+		assert.Equal(t, 11, syntaxErr.Position)
+		assert.Nil(t, list)
+		mockJournalRepo.AssertNotCalled(t, "GetJournalList", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestJournalService_GetJournalList_Fuzzy(t *testing.T) {
+	mockJournalRepo := new(mocks.JournalRepository)
+	mockEmbeddingHTTP := new(mocks.EmbeddingHTTPRepository)
+	journalService := service.NewJournalService(mockJournalRepo, mockEmbeddingHTTP)
+
+	ctx := context.Background()
+	limit, page := 10, 0
+
+	t.Run("Scores fuzzy matches by trigram similarity", func(t *testing.T) {
+		filter := &domain.JournalFilter{Limit: &limit, Page: &page, Search: "ibuprofin", Fuzzy: true}
+		mockJournalRepo.On("GetJournalList", mock.Anything, mock.Anything, mock.AnythingOfType("domain.QueryEmbeddings")).
+			Return([]domain.JournalResponse{{PMID: 2, LexicalScore: 0.7}}, nil).Once()
+
+		list, err := journalService.GetJournalList(ctx, filter)
+
+		assert.NoError(t, err)
+		assert.Equal(t, domain.TrigramSimilarityScore, list.Journals[0].ScoreType)
+		assert.Empty(t, list.Meta.Suggestions)
+		mockJournalRepo.AssertNotCalled(t, "GetSearchSuggestions", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Suggests queries when nothing matched", func(t *testing.T) {
+		filter := &domain.JournalFilter{Limit: &limit, Page: &page, Search: `ibuprofin NOT mesh:Mice`}
+		mockJournalRepo.On("GetJournalList", mock.Anything, mock.Anything, mock.AnythingOfType("domain.QueryEmbeddings")).
+			Return([]domain.JournalResponse{}, nil).Once()
+		mockJournalRepo.On("GetSearchSuggestions", mock.Anything, "ibuprofin", domain.MaxSearchSuggestions).
+			Return([]string{"ibuprofen", `mesh:"Ibuprofen"`}, nil).Once()
+
+		list, err := journalService.GetJournalList(ctx, filter)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"ibuprofen", `mesh:"Ibuprofen"`}, list.Meta.Suggestions)
+		mockJournalRepo.AssertExpectations(t)
+	})
+
+	t.Run("Does not suggest past the first page", func(t *testing.T) {
+		next := 3
+		filter := &domain.JournalFilter{Limit: &limit, Page: &next, Search: "ibuprofin"}
+		mockJournalRepo.On("GetJournalList", mock.Anything, mock.Anything, mock.AnythingOfType("domain.QueryEmbeddings")).
+			Return([]domain.JournalResponse{}, nil).Once()
+
+		list, err := journalService.GetJournalList(ctx, filter)
+
+		assert.NoError(t, err)
+		assert.Empty(t, list.Meta.Suggestions)
+	})
+
+	t.Run("Rejects fuzzy without words", func(t *testing.T) {
+		for _, search := range []string{"", "mesh:Ibuprofen"} {
+			filter := &domain.JournalFilter{Limit: &limit, Search: search, Fuzzy: true}
+
+			list, err := journalService.GetJournalList(ctx, filter)
+
+			assert.ErrorIs(t, err, domain.ErrBadParamInput, search)
+			assert.Nil(t, list)
+		}
+	})
+}
+
+func TestJournalService_GetJournalList_Bibliography(t *testing.T) {
+	mockJournalRepo := new(mocks.JournalRepository)
+	mockEmbeddingHTTP := new(mocks.EmbeddingHTTPRepository)
+	journalService := service.NewJournalService(mockJournalRepo, mockEmbeddingHTTP)
+
+	ctx := context.Background()
+	limit := 10
+
+	t.Run("Rejects malformed and inverted dates", func(t *testing.T) {
+		for _, filter := range []*domain.JournalFilter{
+			{Limit: &limit, PublishedFrom: "14/03/2019"},
+			{Limit: &limit, PublishedTo: "2019-13"},
+			{Limit: &limit, PublishedFrom: "2021", PublishedTo: "2020-12-31"},
+		} {
+			list, err := journalService.GetJournalList(ctx, filter)
+
+			assert.ErrorIs(t, err, domain.ErrBadParamInput, filter.PublishedFrom+".."+filter.PublishedTo)
+			assert.Nil(t, list)
+		}
+	})
+}
+
+func TestJournalService_GetMeSHFacets(t *testing.T) {
+	mockJournalRepo := new(mocks.JournalRepository)
+	mockEmbeddingHTTP := new(mocks.EmbeddingHTTPRepository)
+	journalService := service.NewJournalService(mockJournalRepo, mockEmbeddingHTTP)
+
+	ctx := context.Background()
+	expectedFacets := []domain.FacetCount{{Value: "Neoplasms", Count: 12}}
+
+	t.Run("Facets the top candidates of a vector search", func(t *testing.T) {
+		depth := 50
+		embedding := pgvector.NewVector([]float32{0.1, 0.2})
+		filter := &domain.JournalFacetFilter{
+			JournalFilter: domain.JournalFilter{VSearch: "tumour growth", Type: domain.GeneralVectorType},
+			Depth:         &depth,
+		}
+		mockEmbeddingHTTP.On("GetGeneralEmbedding", mock.Anything, "tumour growth", domain.GeneralVectorType).
+			Return(&embedding, nil).Once()
+		mockJournalRepo.On("GetMeSHFacets", mock.Anything, &filter.JournalFilter, domain.QueryEmbeddings{filter.Type: embedding}, domain.DefaultFacetSize, depth).
+			Return(expectedFacets, nil).Once()
+
+		facets, err := journalService.GetMeSHFacets(ctx, filter)
+
+		assert.NoError(t, err)
+		assert.Equal(t, expectedFacets, facets)
+
+		mockEmbeddingHTTP.AssertExpectations(t)
+		mockJournalRepo.AssertExpectations(t)
+	})
+
+	t.Run("Rejects an oversized facet size", func(t *testing.T) {
+		size := domain.MaxFacetSize + 1
+		filter := &domain.JournalFacetFilter{Size: &size}
+
+		facets, err := journalService.GetMeSHFacets(ctx, filter)
+
+		assert.ErrorIs(t, err, domain.ErrBadParamInput)
+		assert.Nil(t, facets)
+	})
+}
+
+func TestJournalService_ImportJournalSignals(t *testing.T) {
+	mockJournalRepo := new(mocks.JournalRepository)
+	mockEmbeddingHTTP := new(mocks.EmbeddingHTTPRepository)
+	journalService := service.NewJournalService(mockJournalRepo, mockEmbeddingHTTP)
+
+	ctx := context.Background()
+
+	t.Run("Replaces the stored values of the signal", func(t *testing.T) {
+		signals := []domain.JournalSignal{
+			{PMID: 1, Name: domain.CitationCountSignal, Value: 450},
+			{PMID: 2, Name: domain.CitationCountSignal, Value: 0},
+		}
+		mockJournalRepo.On("ReplaceJournalSignals", mock.Anything, domain.CitationCountSignal, signals).
+			Return(nil).Once()
+
+		err := journalService.ImportJournalSignals(ctx, domain.CitationCountSignal, signals)
+
+		assert.NoError(t, err)
+		mockJournalRepo.AssertExpectations(t)
+	})
+
+	t.Run("Rejects negative values", func(t *testing.T) {
+		signals := []domain.JournalSignal{{PMID: 1, Name: domain.CitationCountSignal, Value: -1}}
+
+		err := journalService.ImportJournalSignals(ctx, domain.CitationCountSignal, signals)
+
+		assert.ErrorIs(t, err, domain.ErrBadParamInput)
+		mockJournalRepo.AssertExpectations(t)
+	})
+}
+
+func TestJournalService_GetSimilarJournals(t *testing.T) {
+	mockJournalRepo := new(mocks.JournalRepository)
+	mockEmbeddingHTTP := new(mocks.EmbeddingHTTPRepository)
+	journalService := service.NewJournalService(mockJournalRepo, mockEmbeddingHTTP)
+
+	ctx := context.Background()
+	pmid := int64(12345)
+
+	t.Run("Defaults to the generalist embeddings", func(t *testing.T) {
+		filter := &domain.SimilarJournalFilter{}
+		mockJournalRepo.On(
+			"GetSimilarJournals",
+			mock.Anything,
+			pmid,
+			&domain.SimilarJournalFilter{Type: domain.GeneralVectorType},
+		).Return([]domain.JournalResponse{{PMID: 2}, {PMID: 3}}, nil).Once()
+
+		journals, err := journalService.GetSimilarJournals(ctx, pmid, filter)
+
+		assert.NoError(t, err)
+		assert.Len(t, journals, 2)
+
+		mockJournalRepo.AssertExpectations(t)
+	})
+
+	t.Run("Returns not found when the journal has no embedding", func(t *testing.T) {
+		filter := &domain.SimilarJournalFilter{Type: domain.SpecialistVectorType}
+		mockJournalRepo.On("GetSimilarJournals", mock.Anything, pmid, filter).
+			Return(nil, domain.ErrNotFound).Once()
+
+		journals, err := journalService.GetSimilarJournals(ctx, pmid, filter)
+
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+		assert.Nil(t, journals)
+
+		mockJournalRepo.AssertExpectations(t)
+	})
+
+	t.Run("Rejects unknown vector type", func(t *testing.T) {
+		filter := &domain.SimilarJournalFilter{Type: "unknown"}
+
+		journals, err := journalService.GetSimilarJournals(ctx, pmid, filter)
+
+		assert.ErrorIs(t, err, domain.ErrBadParamInput)
+		assert.Nil(t, journals)
+	})
+}
+
+func TestJournalService_SuggestJournals(t *testing.T) {
+	mockJournalRepo := new(mocks.JournalRepository)
+	mockEmbeddingHTTP := new(mocks.EmbeddingHTTPRepository)
+	journalService := service.NewJournalService(mockJournalRepo, mockEmbeddingHTTP)
+
+	ctx := context.Background()
+
+	t.Run("Completes the prefix", func(t *testing.T) {
+		pmid := int64(31)
+		filter := &domain.SuggestFilter{Prefix: "ibupro"}
+		mockJournalRepo.On("GetSuggestions", mock.Anything, "ibupro", domain.DefaultSuggestLimit).
+			Return([]domain.Suggestion{
+				{Text: "Ibuprofen", Source: domain.MeSHSuggestion, Score: 0.6},
+				{Text: "Ibuprofen versus placebo", Source: domain.TitleSuggestion, PMID: &pmid, Score: 0.3},
+			}, nil).Once()
+
+		suggestions, err := journalService.SuggestJournals(ctx, filter)
+
+		assert.NoError(t, err)
+		assert.Len(t, suggestions, 2)
+		assert.Equal(t, domain.MeSHSuggestion, suggestions[0].Source)
+
+		mockJournalRepo.AssertExpectations(t)
+	})
+
+	t.Run("Rejects short prefixes and large limits", func(t *testing.T) {
+		limit := domain.MaxSuggestLimit + 1
+		for _, filter := range []*domain.SuggestFilter{
+			{Prefix: " i "},
+			{Prefix: "ibupro", Limit: &limit},
+		} {
+			suggestions, err := journalService.SuggestJournals(ctx, filter)
+
+			assert.ErrorIs(t, err, domain.ErrBadParamInput)
+			assert.Nil(t, suggestions)
+		}
+		mockJournalRepo.AssertNotCalled(t, "GetSuggestions", mock.Anything, " i ", mock.Anything)
+	})
 }
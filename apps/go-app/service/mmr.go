@@ -0,0 +1,79 @@
+package service
+
+import (
+	"go-app/domain"
+	"math"
+)
+
+// maximalMarginalRelevance greedily picks up to n candidates, each time taking
+// the one maximizing lambda*relevance - (1-lambda)*redundancy. Relevance is
+// the distance min-max normalized over the candidates, so that fused scores
+// are on the same scale as the cosine similarity between candidate embeddings
+// the redundancy is measured with.
+func maximalMarginalRelevance(
+	candidates []domain.JournalCandidate,
+	lambda float64,
+	n int,
+) []domain.JournalResponse {
+	n = min(n, len(candidates))
+	if n == 0 {
+		return nil
+	}
+
+	lo, hi := math.Inf(1), math.Inf(-1)
+	for _, c := range candidates {
+		lo, hi = math.Min(lo, c.Distance), math.Max(hi, c.Distance)
+	}
+	relevance := make([]float64, len(candidates))
+	for i, c := range candidates {
+		relevance[i] = 1
+		if hi > lo {
+			relevance[i] = (c.Distance - lo) / (hi - lo)
+		}
+	}
+
+	vectors := make([][]float32, len(candidates))
+	for i, c := range candidates {
+		vectors[i] = c.Embedding.Slice()
+	}
+
+	// redundancy[i] is the highest similarity of candidate i to a pick.
+	redundancy := make([]float64, len(candidates))
+	picked := make([]bool, len(candidates))
+	result := make([]domain.JournalResponse, 0, n)
+	for len(result) < n {
+		best, bestScore := -1, math.Inf(-1)
+		for i := range candidates {
+			if picked[i] {
+				continue
+			}
+			score := lambda*relevance[i] - (1-lambda)*redundancy[i]
+			if score > bestScore {
+				best, bestScore = i, score
+			}
+		}
+
+		picked[best] = true
+		result = append(result, candidates[best].JournalResponse)
+		for i := range candidates {
+			if !picked[i] {
+				redundancy[i] = math.Max(redundancy[i], cosineSimilarity(vectors[i], vectors[best]))
+			}
+		}
+	}
+
+	return result
+}
+
+func cosineSimilarity(a, b []float32) float64 {
+	var dot, normA, normB float64
+	for i := range min(len(a), len(b)) {
+		dot += float64(a[i]) * float64(b[i])
+		normA += float64(a[i]) * float64(a[i])
+		normB += float64(b[i]) * float64(b[i])
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / math.Sqrt(normA*normB)
+}
@@ -112,3 +112,77 @@ func (_c *EmbeddingHTTPRepository_GetGeneralEmbedding_Call) RunAndReturn(run fun
 	_c.Call.Return(run)
 	return _c
 }
+
+// GetRerankScores provides a mock function for the type EmbeddingHTTPRepository
+func (_mock *EmbeddingHTTPRepository) GetRerankScores(ctx context.Context, query string, documents []string) ([]float64, error) {
+	ret := _mock.Called(ctx, query, documents)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetRerankScores")
+	}
+
+	var r0 []float64
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, []string) ([]float64, error)); ok {
+		return returnFunc(ctx, query, documents)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, []string) []float64); ok {
+		r0 = returnFunc(ctx, query, documents)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]float64)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, []string) error); ok {
+		r1 = returnFunc(ctx, query, documents)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// EmbeddingHTTPRepository_GetRerankScores_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetRerankScores'
+type EmbeddingHTTPRepository_GetRerankScores_Call struct {
+	*mock.Call
+}
+
+// GetRerankScores is a helper method to define mock.On call
+//   - ctx context.Context
+//   - query string
+//   - documents []string
+func (_e *EmbeddingHTTPRepository_Expecter) GetRerankScores(ctx interface{}, query interface{}, documents interface{}) *EmbeddingHTTPRepository_GetRerankScores_Call {
+	return &EmbeddingHTTPRepository_GetRerankScores_Call{Call: _e.mock.On("GetRerankScores", ctx, query, documents)}
+}
+
+func (_c *EmbeddingHTTPRepository_GetRerankScores_Call) Run(run func(ctx context.Context, query string, documents []string)) *EmbeddingHTTPRepository_GetRerankScores_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 []string
+		if args[2] != nil {
+			arg2 = args[2].([]string)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *EmbeddingHTTPRepository_GetRerankScores_Call) Return(float64s []float64, err error) *EmbeddingHTTPRepository_GetRerankScores_Call {
+	_c.Call.Return(float64s, err)
+	return _c
+}
+
+func (_c *EmbeddingHTTPRepository_GetRerankScores_Call) RunAndReturn(run func(ctx context.Context, query string, documents []string) ([]float64, error)) *EmbeddingHTTPRepository_GetRerankScores_Call {
+	_c.Call.Return(run)
+	return _c
+}
@@ -8,8 +8,6 @@ import (
 	"context"
 	"go-app/domain"
 
-	"github.com/google/uuid"
-	"github.com/pgvector/pgvector-go"
 	mock "github.com/stretchr/testify/mock"
 )
 
@@ -40,9 +38,161 @@ func (_m *JournalRepository) EXPECT() *JournalRepository_Expecter {
 	return &JournalRepository_Expecter{mock: &_m.Mock}
 }
 
+// CountJournals provides a mock function for the type JournalRepository
+func (_mock *JournalRepository) CountJournals(ctx context.Context, filter *domain.JournalFilter, embeddings domain.QueryEmbeddings, exact bool) (int64, error) {
+	ret := _mock.Called(ctx, filter, embeddings, exact)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CountJournals")
+	}
+
+	var r0 int64
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *domain.JournalFilter, domain.QueryEmbeddings, bool) (int64, error)); ok {
+		return returnFunc(ctx, filter, embeddings, exact)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *domain.JournalFilter, domain.QueryEmbeddings, bool) int64); ok {
+		r0 = returnFunc(ctx, filter, embeddings, exact)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *domain.JournalFilter, domain.QueryEmbeddings, bool) error); ok {
+		r1 = returnFunc(ctx, filter, embeddings, exact)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// JournalRepository_CountJournals_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CountJournals'
+type JournalRepository_CountJournals_Call struct {
+	*mock.Call
+}
+
+// CountJournals is a helper method to define mock.On call
+//   - ctx context.Context
+//   - filter *domain.JournalFilter
+//   - embeddings domain.QueryEmbeddings
+//   - exact bool
+func (_e *JournalRepository_Expecter) CountJournals(ctx interface{}, filter interface{}, embeddings interface{}, exact interface{}) *JournalRepository_CountJournals_Call {
+	return &JournalRepository_CountJournals_Call{Call: _e.mock.On("CountJournals", ctx, filter, embeddings, exact)}
+}
+
+func (_c *JournalRepository_CountJournals_Call) Run(run func(ctx context.Context, filter *domain.JournalFilter, embeddings domain.QueryEmbeddings, exact bool)) *JournalRepository_CountJournals_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *domain.JournalFilter
+		if args[1] != nil {
+			arg1 = args[1].(*domain.JournalFilter)
+		}
+		var arg2 domain.QueryEmbeddings
+		if args[2] != nil {
+			arg2 = args[2].(domain.QueryEmbeddings)
+		}
+		var arg3 bool
+		if args[3] != nil {
+			arg3 = args[3].(bool)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *JournalRepository_CountJournals_Call) Return(n int64, err error) *JournalRepository_CountJournals_Call {
+	_c.Call.Return(n, err)
+	return _c
+}
+
+func (_c *JournalRepository_CountJournals_Call) RunAndReturn(run func(ctx context.Context, filter *domain.JournalFilter, embeddings domain.QueryEmbeddings, exact bool) (int64, error)) *JournalRepository_CountJournals_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetHighlights provides a mock function for the type JournalRepository
+func (_mock *JournalRepository) GetHighlights(ctx context.Context, query string, pmids []int64) ([]domain.JournalHighlights, error) {
+	ret := _mock.Called(ctx, query, pmids)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetHighlights")
+	}
+
+	var r0 []domain.JournalHighlights
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, []int64) ([]domain.JournalHighlights, error)); ok {
+		return returnFunc(ctx, query, pmids)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, []int64) []domain.JournalHighlights); ok {
+		r0 = returnFunc(ctx, query, pmids)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.JournalHighlights)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, []int64) error); ok {
+		r1 = returnFunc(ctx, query, pmids)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// JournalRepository_GetHighlights_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetHighlights'
+type JournalRepository_GetHighlights_Call struct {
+	*mock.Call
+}
+
+// GetHighlights is a helper method to define mock.On call
+//   - ctx context.Context
+//   - query string
+//   - pmids []int64
+func (_e *JournalRepository_Expecter) GetHighlights(ctx interface{}, query interface{}, pmids interface{}) *JournalRepository_GetHighlights_Call {
+	return &JournalRepository_GetHighlights_Call{Call: _e.mock.On("GetHighlights", ctx, query, pmids)}
+}
+
+func (_c *JournalRepository_GetHighlights_Call) Run(run func(ctx context.Context, query string, pmids []int64)) *JournalRepository_GetHighlights_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 []int64
+		if args[2] != nil {
+			arg2 = args[2].([]int64)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *JournalRepository_GetHighlights_Call) Return(journalHighlightss []domain.JournalHighlights, err error) *JournalRepository_GetHighlights_Call {
+	_c.Call.Return(journalHighlightss, err)
+	return _c
+}
+
+func (_c *JournalRepository_GetHighlights_Call) RunAndReturn(run func(ctx context.Context, query string, pmids []int64) ([]domain.JournalHighlights, error)) *JournalRepository_GetHighlights_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // GetJournal provides a mock function for the type JournalRepository
-func (_mock *JournalRepository) GetJournal(ctx context.Context, id uuid.UUID) (*domain.Journal, error) {
-	ret := _mock.Called(ctx, id)
+func (_mock *JournalRepository) GetJournal(ctx context.Context, pmid int64, fields []string) (*domain.Journal, error) {
+	ret := _mock.Called(ctx, pmid, fields)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetJournal")
@@ -50,18 +200,18 @@ func (_mock *JournalRepository) GetJournal(ctx context.Context, id uuid.UUID) (*
 
 	var r0 *domain.Journal
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID) (*domain.Journal, error)); ok {
-		return returnFunc(ctx, id)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int64, []string) (*domain.Journal, error)); ok {
+		return returnFunc(ctx, pmid, fields)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID) *domain.Journal); ok {
-		r0 = returnFunc(ctx, id)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int64, []string) *domain.Journal); ok {
+		r0 = returnFunc(ctx, pmid, fields)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*domain.Journal)
 		}
 	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
-		r1 = returnFunc(ctx, id)
+	if returnFunc, ok := ret.Get(1).(func(context.Context, int64, []string) error); ok {
+		r1 = returnFunc(ctx, pmid, fields)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -75,24 +225,30 @@ type JournalRepository_GetJournal_Call struct {
 
 // GetJournal is a helper method to define mock.On call
 //   - ctx context.Context
-//   - id uuid.UUID
-func (_e *JournalRepository_Expecter) GetJournal(ctx interface{}, id interface{}) *JournalRepository_GetJournal_Call {
-	return &JournalRepository_GetJournal_Call{Call: _e.mock.On("GetJournal", ctx, id)}
+//   - pmid int64
+//   - fields []string
+func (_e *JournalRepository_Expecter) GetJournal(ctx interface{}, pmid interface{}, fields interface{}) *JournalRepository_GetJournal_Call {
+	return &JournalRepository_GetJournal_Call{Call: _e.mock.On("GetJournal", ctx, pmid, fields)}
 }
 
-func (_c *JournalRepository_GetJournal_Call) Run(run func(ctx context.Context, id uuid.UUID)) *JournalRepository_GetJournal_Call {
+func (_c *JournalRepository_GetJournal_Call) Run(run func(ctx context.Context, pmid int64, fields []string)) *JournalRepository_GetJournal_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
-		var arg1 uuid.UUID
+		var arg1 int64
 		if args[1] != nil {
-			arg1 = args[1].(uuid.UUID)
+			arg1 = args[1].(int64)
+		}
+		var arg2 []string
+		if args[2] != nil {
+			arg2 = args[2].([]string)
 		}
 		run(
 			arg0,
 			arg1,
+			arg2,
 		)
 	})
 	return _c
@@ -103,14 +259,88 @@ func (_c *JournalRepository_GetJournal_Call) Return(journal *domain.Journal, err
 	return _c
 }
 
-func (_c *JournalRepository_GetJournal_Call) RunAndReturn(run func(ctx context.Context, id uuid.UUID) (*domain.Journal, error)) *JournalRepository_GetJournal_Call {
+func (_c *JournalRepository_GetJournal_Call) RunAndReturn(run func(ctx context.Context, pmid int64, fields []string) (*domain.Journal, error)) *JournalRepository_GetJournal_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetJournalCandidates provides a mock function for the type JournalRepository
+func (_mock *JournalRepository) GetJournalCandidates(ctx context.Context, filter *domain.JournalFilter, embeddings domain.QueryEmbeddings) ([]domain.JournalCandidate, error) {
+	ret := _mock.Called(ctx, filter, embeddings)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetJournalCandidates")
+	}
+
+	var r0 []domain.JournalCandidate
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *domain.JournalFilter, domain.QueryEmbeddings) ([]domain.JournalCandidate, error)); ok {
+		return returnFunc(ctx, filter, embeddings)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *domain.JournalFilter, domain.QueryEmbeddings) []domain.JournalCandidate); ok {
+		r0 = returnFunc(ctx, filter, embeddings)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.JournalCandidate)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *domain.JournalFilter, domain.QueryEmbeddings) error); ok {
+		r1 = returnFunc(ctx, filter, embeddings)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// JournalRepository_GetJournalCandidates_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetJournalCandidates'
+type JournalRepository_GetJournalCandidates_Call struct {
+	*mock.Call
+}
+
+// GetJournalCandidates is a helper method to define mock.On call
+//   - ctx context.Context
+//   - filter *domain.JournalFilter
+//   - embeddings domain.QueryEmbeddings
+func (_e *JournalRepository_Expecter) GetJournalCandidates(ctx interface{}, filter interface{}, embeddings interface{}) *JournalRepository_GetJournalCandidates_Call {
+	return &JournalRepository_GetJournalCandidates_Call{Call: _e.mock.On("GetJournalCandidates", ctx, filter, embeddings)}
+}
+
+func (_c *JournalRepository_GetJournalCandidates_Call) Run(run func(ctx context.Context, filter *domain.JournalFilter, embeddings domain.QueryEmbeddings)) *JournalRepository_GetJournalCandidates_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *domain.JournalFilter
+		if args[1] != nil {
+			arg1 = args[1].(*domain.JournalFilter)
+		}
+		var arg2 domain.QueryEmbeddings
+		if args[2] != nil {
+			arg2 = args[2].(domain.QueryEmbeddings)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *JournalRepository_GetJournalCandidates_Call) Return(journalCandidates []domain.JournalCandidate, err error) *JournalRepository_GetJournalCandidates_Call {
+	_c.Call.Return(journalCandidates, err)
+	return _c
+}
+
+func (_c *JournalRepository_GetJournalCandidates_Call) RunAndReturn(run func(ctx context.Context, filter *domain.JournalFilter, embeddings domain.QueryEmbeddings) ([]domain.JournalCandidate, error)) *JournalRepository_GetJournalCandidates_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
 // GetJournalList provides a mock function for the type JournalRepository
-func (_mock *JournalRepository) GetJournalList(ctx context.Context, filter *domain.JournalFilter, embedding *pgvector.Vector) ([]domain.JournalResponse, error) {
-	ret := _mock.Called(ctx, filter, embedding)
+func (_mock *JournalRepository) GetJournalList(ctx context.Context, filter *domain.JournalFilter, embeddings domain.QueryEmbeddings) ([]domain.JournalResponse, error) {
+	ret := _mock.Called(ctx, filter, embeddings)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetJournalList")
@@ -118,18 +348,18 @@ func (_mock *JournalRepository) GetJournalList(ctx context.Context, filter *doma
 
 	var r0 []domain.JournalResponse
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, *domain.JournalFilter, *pgvector.Vector) ([]domain.JournalResponse, error)); ok {
-		return returnFunc(ctx, filter, embedding)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *domain.JournalFilter, domain.QueryEmbeddings) ([]domain.JournalResponse, error)); ok {
+		return returnFunc(ctx, filter, embeddings)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, *domain.JournalFilter, *pgvector.Vector) []domain.JournalResponse); ok {
-		r0 = returnFunc(ctx, filter, embedding)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *domain.JournalFilter, domain.QueryEmbeddings) []domain.JournalResponse); ok {
+		r0 = returnFunc(ctx, filter, embeddings)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]domain.JournalResponse)
 		}
 	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context, *domain.JournalFilter, *pgvector.Vector) error); ok {
-		r1 = returnFunc(ctx, filter, embedding)
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *domain.JournalFilter, domain.QueryEmbeddings) error); ok {
+		r1 = returnFunc(ctx, filter, embeddings)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -144,12 +374,12 @@ type JournalRepository_GetJournalList_Call struct {
 // GetJournalList is a helper method to define mock.On call
 //   - ctx context.Context
 //   - filter *domain.JournalFilter
-//   - embedding *pgvector.Vector
-func (_e *JournalRepository_Expecter) GetJournalList(ctx interface{}, filter interface{}, embedding interface{}) *JournalRepository_GetJournalList_Call {
-	return &JournalRepository_GetJournalList_Call{Call: _e.mock.On("GetJournalList", ctx, filter, embedding)}
+//   - embeddings domain.QueryEmbeddings
+func (_e *JournalRepository_Expecter) GetJournalList(ctx interface{}, filter interface{}, embeddings interface{}) *JournalRepository_GetJournalList_Call {
+	return &JournalRepository_GetJournalList_Call{Call: _e.mock.On("GetJournalList", ctx, filter, embeddings)}
 }
 
-func (_c *JournalRepository_GetJournalList_Call) Run(run func(ctx context.Context, filter *domain.JournalFilter, embedding *pgvector.Vector)) *JournalRepository_GetJournalList_Call {
+func (_c *JournalRepository_GetJournalList_Call) Run(run func(ctx context.Context, filter *domain.JournalFilter, embeddings domain.QueryEmbeddings)) *JournalRepository_GetJournalList_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
@@ -159,9 +389,9 @@ func (_c *JournalRepository_GetJournalList_Call) Run(run func(ctx context.Contex
 		if args[1] != nil {
 			arg1 = args[1].(*domain.JournalFilter)
 		}
-		var arg2 *pgvector.Vector
+		var arg2 domain.QueryEmbeddings
 		if args[2] != nil {
-			arg2 = args[2].(*pgvector.Vector)
+			arg2 = args[2].(domain.QueryEmbeddings)
 		}
 		run(
 			arg0,
@@ -177,7 +407,520 @@ func (_c *JournalRepository_GetJournalList_Call) Return(journalResponses []domai
 	return _c
 }
 
-func (_c *JournalRepository_GetJournalList_Call) RunAndReturn(run func(ctx context.Context, filter *domain.JournalFilter, embedding *pgvector.Vector) ([]domain.JournalResponse, error)) *JournalRepository_GetJournalList_Call {
+func (_c *JournalRepository_GetJournalList_Call) RunAndReturn(run func(ctx context.Context, filter *domain.JournalFilter, embeddings domain.QueryEmbeddings) ([]domain.JournalResponse, error)) *JournalRepository_GetJournalList_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetJournalSignals provides a mock function for the type JournalRepository
+func (_mock *JournalRepository) GetJournalSignals(ctx context.Context, pmids []int64, names []string) ([]domain.JournalSignal, error) {
+	ret := _mock.Called(ctx, pmids, names)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetJournalSignals")
+	}
+
+	var r0 []domain.JournalSignal
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []int64, []string) ([]domain.JournalSignal, error)); ok {
+		return returnFunc(ctx, pmids, names)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []int64, []string) []domain.JournalSignal); ok {
+		r0 = returnFunc(ctx, pmids, names)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.JournalSignal)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, []int64, []string) error); ok {
+		r1 = returnFunc(ctx, pmids, names)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// JournalRepository_GetJournalSignals_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetJournalSignals'
+type JournalRepository_GetJournalSignals_Call struct {
+	*mock.Call
+}
+
+// GetJournalSignals is a helper method to define mock.On call
+//   - ctx context.Context
+//   - pmids []int64
+//   - names []string
+func (_e *JournalRepository_Expecter) GetJournalSignals(ctx interface{}, pmids interface{}, names interface{}) *JournalRepository_GetJournalSignals_Call {
+	return &JournalRepository_GetJournalSignals_Call{Call: _e.mock.On("GetJournalSignals", ctx, pmids, names)}
+}
+
+func (_c *JournalRepository_GetJournalSignals_Call) Run(run func(ctx context.Context, pmids []int64, names []string)) *JournalRepository_GetJournalSignals_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 []int64
+		if args[1] != nil {
+			arg1 = args[1].([]int64)
+		}
+		var arg2 []string
+		if args[2] != nil {
+			arg2 = args[2].([]string)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *JournalRepository_GetJournalSignals_Call) Return(journalSignals []domain.JournalSignal, err error) *JournalRepository_GetJournalSignals_Call {
+	_c.Call.Return(journalSignals, err)
+	return _c
+}
+
+func (_c *JournalRepository_GetJournalSignals_Call) RunAndReturn(run func(ctx context.Context, pmids []int64, names []string) ([]domain.JournalSignal, error)) *JournalRepository_GetJournalSignals_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetJournalsByPMIDs provides a mock function for the type JournalRepository
+func (_mock *JournalRepository) GetJournalsByPMIDs(ctx context.Context, pmids []int64) ([]domain.Journal, error) {
+	ret := _mock.Called(ctx, pmids)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetJournalsByPMIDs")
+	}
+
+	var r0 []domain.Journal
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []int64) ([]domain.Journal, error)); ok {
+		return returnFunc(ctx, pmids)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []int64) []domain.Journal); ok {
+		r0 = returnFunc(ctx, pmids)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Journal)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, []int64) error); ok {
+		r1 = returnFunc(ctx, pmids)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// JournalRepository_GetJournalsByPMIDs_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetJournalsByPMIDs'
+type JournalRepository_GetJournalsByPMIDs_Call struct {
+	*mock.Call
+}
+
+// GetJournalsByPMIDs is a helper method to define mock.On call
+//   - ctx context.Context
+//   - pmids []int64
+func (_e *JournalRepository_Expecter) GetJournalsByPMIDs(ctx interface{}, pmids interface{}) *JournalRepository_GetJournalsByPMIDs_Call {
+	return &JournalRepository_GetJournalsByPMIDs_Call{Call: _e.mock.On("GetJournalsByPMIDs", ctx, pmids)}
+}
+
+func (_c *JournalRepository_GetJournalsByPMIDs_Call) Run(run func(ctx context.Context, pmids []int64)) *JournalRepository_GetJournalsByPMIDs_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 []int64
+		if args[1] != nil {
+			arg1 = args[1].([]int64)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *JournalRepository_GetJournalsByPMIDs_Call) Return(journals []domain.Journal, err error) *JournalRepository_GetJournalsByPMIDs_Call {
+	_c.Call.Return(journals, err)
+	return _c
+}
+
+func (_c *JournalRepository_GetJournalsByPMIDs_Call) RunAndReturn(run func(ctx context.Context, pmids []int64) ([]domain.Journal, error)) *JournalRepository_GetJournalsByPMIDs_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetMeSHFacets provides a mock function for the type JournalRepository
+func (_mock *JournalRepository) GetMeSHFacets(ctx context.Context, filter *domain.JournalFilter, embeddings domain.QueryEmbeddings, size int, depth int) ([]domain.FacetCount, error) {
+	ret := _mock.Called(ctx, filter, embeddings, size, depth)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetMeSHFacets")
+	}
+
+	var r0 []domain.FacetCount
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *domain.JournalFilter, domain.QueryEmbeddings, int, int) ([]domain.FacetCount, error)); ok {
+		return returnFunc(ctx, filter, embeddings, size, depth)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *domain.JournalFilter, domain.QueryEmbeddings, int, int) []domain.FacetCount); ok {
+		r0 = returnFunc(ctx, filter, embeddings, size, depth)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.FacetCount)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *domain.JournalFilter, domain.QueryEmbeddings, int, int) error); ok {
+		r1 = returnFunc(ctx, filter, embeddings, size, depth)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// JournalRepository_GetMeSHFacets_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetMeSHFacets'
+type JournalRepository_GetMeSHFacets_Call struct {
+	*mock.Call
+}
+
+// GetMeSHFacets is a helper method to define mock.On call
+//   - ctx context.Context
+//   - filter *domain.JournalFilter
+//   - embeddings domain.QueryEmbeddings
+//   - size int
+//   - depth int
+func (_e *JournalRepository_Expecter) GetMeSHFacets(ctx interface{}, filter interface{}, embeddings interface{}, size interface{}, depth interface{}) *JournalRepository_GetMeSHFacets_Call {
+	return &JournalRepository_GetMeSHFacets_Call{Call: _e.mock.On("GetMeSHFacets", ctx, filter, embeddings, size, depth)}
+}
+
+func (_c *JournalRepository_GetMeSHFacets_Call) Run(run func(ctx context.Context, filter *domain.JournalFilter, embeddings domain.QueryEmbeddings, size int, depth int)) *JournalRepository_GetMeSHFacets_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *domain.JournalFilter
+		if args[1] != nil {
+			arg1 = args[1].(*domain.JournalFilter)
+		}
+		var arg2 domain.QueryEmbeddings
+		if args[2] != nil {
+			arg2 = args[2].(domain.QueryEmbeddings)
+		}
+		var arg3 int
+		if args[3] != nil {
+			arg3 = args[3].(int)
+		}
+		var arg4 int
+		if args[4] != nil {
+			arg4 = args[4].(int)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+			arg4,
+		)
+	})
+	return _c
+}
+
+func (_c *JournalRepository_GetMeSHFacets_Call) Return(facetCounts []domain.FacetCount, err error) *JournalRepository_GetMeSHFacets_Call {
+	_c.Call.Return(facetCounts, err)
+	return _c
+}
+
+func (_c *JournalRepository_GetMeSHFacets_Call) RunAndReturn(run func(ctx context.Context, filter *domain.JournalFilter, embeddings domain.QueryEmbeddings, size int, depth int) ([]domain.FacetCount, error)) *JournalRepository_GetMeSHFacets_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetSearchSuggestions provides a mock function for the type JournalRepository
+func (_mock *JournalRepository) GetSearchSuggestions(ctx context.Context, keywords string, size int) ([]string, error) {
+	ret := _mock.Called(ctx, keywords, size)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetSearchSuggestions")
+	}
+
+	var r0 []string
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, int) ([]string, error)); ok {
+		return returnFunc(ctx, keywords, size)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, int) []string); ok {
+		r0 = returnFunc(ctx, keywords, size)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, int) error); ok {
+		r1 = returnFunc(ctx, keywords, size)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// JournalRepository_GetSearchSuggestions_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetSearchSuggestions'
+type JournalRepository_GetSearchSuggestions_Call struct {
+	*mock.Call
+}
+
+// GetSearchSuggestions is a helper method to define mock.On call
+//   - ctx context.Context
+//   - keywords string
+//   - size int
+func (_e *JournalRepository_Expecter) GetSearchSuggestions(ctx interface{}, keywords interface{}, size interface{}) *JournalRepository_GetSearchSuggestions_Call {
+	return &JournalRepository_GetSearchSuggestions_Call{Call: _e.mock.On("GetSearchSuggestions", ctx, keywords, size)}
+}
+
+func (_c *JournalRepository_GetSearchSuggestions_Call) Run(run func(ctx context.Context, keywords string, size int)) *JournalRepository_GetSearchSuggestions_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 int
+		if args[2] != nil {
+			arg2 = args[2].(int)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *JournalRepository_GetSearchSuggestions_Call) Return(strings []string, err error) *JournalRepository_GetSearchSuggestions_Call {
+	_c.Call.Return(strings, err)
+	return _c
+}
+
+func (_c *JournalRepository_GetSearchSuggestions_Call) RunAndReturn(run func(ctx context.Context, keywords string, size int) ([]string, error)) *JournalRepository_GetSearchSuggestions_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetSimilarJournals provides a mock function for the type JournalRepository
+func (_mock *JournalRepository) GetSimilarJournals(ctx context.Context, pmid int64, filter *domain.SimilarJournalFilter) ([]domain.JournalResponse, error) {
+	ret := _mock.Called(ctx, pmid, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetSimilarJournals")
+	}
+
+	var r0 []domain.JournalResponse
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int64, *domain.SimilarJournalFilter) ([]domain.JournalResponse, error)); ok {
+		return returnFunc(ctx, pmid, filter)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int64, *domain.SimilarJournalFilter) []domain.JournalResponse); ok {
+		r0 = returnFunc(ctx, pmid, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.JournalResponse)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, int64, *domain.SimilarJournalFilter) error); ok {
+		r1 = returnFunc(ctx, pmid, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// JournalRepository_GetSimilarJournals_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetSimilarJournals'
+type JournalRepository_GetSimilarJournals_Call struct {
+	*mock.Call
+}
+
+// GetSimilarJournals is a helper method to define mock.On call
+//   - ctx context.Context
+//   - pmid int64
+//   - filter *domain.SimilarJournalFilter
+func (_e *JournalRepository_Expecter) GetSimilarJournals(ctx interface{}, pmid interface{}, filter interface{}) *JournalRepository_GetSimilarJournals_Call {
+	return &JournalRepository_GetSimilarJournals_Call{Call: _e.mock.On("GetSimilarJournals", ctx, pmid, filter)}
+}
+
+func (_c *JournalRepository_GetSimilarJournals_Call) Run(run func(ctx context.Context, pmid int64, filter *domain.SimilarJournalFilter)) *JournalRepository_GetSimilarJournals_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 int64
+		if args[1] != nil {
+			arg1 = args[1].(int64)
+		}
+		var arg2 *domain.SimilarJournalFilter
+		if args[2] != nil {
+			arg2 = args[2].(*domain.SimilarJournalFilter)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *JournalRepository_GetSimilarJournals_Call) Return(journalResponses []domain.JournalResponse, err error) *JournalRepository_GetSimilarJournals_Call {
+	_c.Call.Return(journalResponses, err)
+	return _c
+}
+
+func (_c *JournalRepository_GetSimilarJournals_Call) RunAndReturn(run func(ctx context.Context, pmid int64, filter *domain.SimilarJournalFilter) ([]domain.JournalResponse, error)) *JournalRepository_GetSimilarJournals_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetSuggestions provides a mock function for the type JournalRepository
+func (_mock *JournalRepository) GetSuggestions(ctx context.Context, prefix string, limit int) ([]domain.Suggestion, error) {
+	ret := _mock.Called(ctx, prefix, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetSuggestions")
+	}
+
+	var r0 []domain.Suggestion
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, int) ([]domain.Suggestion, error)); ok {
+		return returnFunc(ctx, prefix, limit)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, int) []domain.Suggestion); ok {
+		r0 = returnFunc(ctx, prefix, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Suggestion)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, int) error); ok {
+		r1 = returnFunc(ctx, prefix, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// JournalRepository_GetSuggestions_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetSuggestions'
+type JournalRepository_GetSuggestions_Call struct {
+	*mock.Call
+}
+
+// GetSuggestions is a helper method to define mock.On call
+//   - ctx context.Context
+//   - prefix string
+//   - limit int
+func (_e *JournalRepository_Expecter) GetSuggestions(ctx interface{}, prefix interface{}, limit interface{}) *JournalRepository_GetSuggestions_Call {
+	return &JournalRepository_GetSuggestions_Call{Call: _e.mock.On("GetSuggestions", ctx, prefix, limit)}
+}
+
+func (_c *JournalRepository_GetSuggestions_Call) Run(run func(ctx context.Context, prefix string, limit int)) *JournalRepository_GetSuggestions_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 int
+		if args[2] != nil {
+			arg2 = args[2].(int)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *JournalRepository_GetSuggestions_Call) Return(suggestions []domain.Suggestion, err error) *JournalRepository_GetSuggestions_Call {
+	_c.Call.Return(suggestions, err)
+	return _c
+}
+
+func (_c *JournalRepository_GetSuggestions_Call) RunAndReturn(run func(ctx context.Context, prefix string, limit int) ([]domain.Suggestion, error)) *JournalRepository_GetSuggestions_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ReplaceJournalSignals provides a mock function for the type JournalRepository
+func (_mock *JournalRepository) ReplaceJournalSignals(ctx context.Context, name string, signals []domain.JournalSignal) error {
+	ret := _mock.Called(ctx, name, signals)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReplaceJournalSignals")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, []domain.JournalSignal) error); ok {
+		r0 = returnFunc(ctx, name, signals)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// JournalRepository_ReplaceJournalSignals_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ReplaceJournalSignals'
+type JournalRepository_ReplaceJournalSignals_Call struct {
+	*mock.Call
+}
+
+// ReplaceJournalSignals is a helper method to define mock.On call
+//   - ctx context.Context
+//   - name string
+//   - signals []domain.JournalSignal
+func (_e *JournalRepository_Expecter) ReplaceJournalSignals(ctx interface{}, name interface{}, signals interface{}) *JournalRepository_ReplaceJournalSignals_Call {
+	return &JournalRepository_ReplaceJournalSignals_Call{Call: _e.mock.On("ReplaceJournalSignals", ctx, name, signals)}
+}
+
+func (_c *JournalRepository_ReplaceJournalSignals_Call) Run(run func(ctx context.Context, name string, signals []domain.JournalSignal)) *JournalRepository_ReplaceJournalSignals_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 []domain.JournalSignal
+		if args[2] != nil {
+			arg2 = args[2].([]domain.JournalSignal)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *JournalRepository_ReplaceJournalSignals_Call) Return(err error) *JournalRepository_ReplaceJournalSignals_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *JournalRepository_ReplaceJournalSignals_Call) RunAndReturn(run func(ctx context.Context, name string, signals []domain.JournalSignal) error) *JournalRepository_ReplaceJournalSignals_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -0,0 +1,78 @@
+package service
+
+import (
+	"go-app/domain"
+	"math"
+)
+
+// pageRank returns the PageRank of the nodes of the citation graph, the
+// journals of the corpus and the journals they cite, summing to 1. A citation
+// passes authority from the citing to the cited journal. The rank of nodes
+// citing nothing, such as journals missing from the corpus, is spread over
+// every node.
+func pageRank(pmids []int64, citations []domain.JournalCitation, damping float64) map[int64]float64 {
+	index := make(map[int64]int, len(pmids))
+	nodes := make([]int64, 0, len(pmids))
+	node := func(pmid int64) int {
+		i, ok := index[pmid]
+		if !ok {
+			i = len(nodes)
+			index[pmid] = i
+			nodes = append(nodes, pmid)
+		}
+		return i
+	}
+	for _, pmid := range pmids {
+		node(pmid)
+	}
+	type edge struct{ from, to int }
+	edges := make([]edge, 0, len(citations))
+	for _, c := range citations {
+		edges = append(edges, edge{from: node(c.CitingPMID), to: node(c.CitedPMID)})
+	}
+
+	n := len(nodes)
+	if n == 0 {
+		return map[int64]float64{}
+	}
+	outDegree := make([]int, n)
+	for _, e := range edges {
+		outDegree[e.from]++
+	}
+
+	rank := make([]float64, n)
+	for i := range rank {
+		rank[i] = 1 / float64(n)
+	}
+	next := make([]float64, n)
+	for range domain.MaxPageRankIterations {
+		dangling := 0.0
+		for i, r := range rank {
+			if outDegree[i] == 0 {
+				dangling += r
+			}
+		}
+		base := ((1 - damping) + damping*dangling) / float64(n)
+		for i := range next {
+			next[i] = base
+		}
+		for _, e := range edges {
+			next[e.to] += damping * rank[e.from] / float64(outDegree[e.from])
+		}
+
+		delta := 0.0
+		for i := range rank {
+			delta += math.Abs(next[i] - rank[i])
+		}
+		rank, next = next, rank
+		if delta < domain.PageRankTolerance {
+			break
+		}
+	}
+
+	ranks := make(map[int64]float64, n)
+	for i, pmid := range nodes {
+		ranks[pmid] = rank[i]
+	}
+	return ranks
+}
@@ -0,0 +1,77 @@
+package service
+
+import (
+	"go-app/domain"
+	"math"
+	"time"
+)
+
+// boostJournals sets the distance of journals to their relevance boosted by
+// settings, and breaks it down in their score components. The relevance is
+// min-max normalized over the journals, like the relevance of maximal marginal
+// relevance, so that it is on the [0, 1] scale of the boosts whatever the
+// search. Journals lacking a signal have a raw value of 0 for it.
+func boostJournals(
+	journals []domain.JournalResponse,
+	relevance []float64,
+	signals []domain.JournalSignal,
+	settings domain.RankingSettings,
+	now time.Time,
+) {
+	lo, hi := math.Inf(1), math.Inf(-1)
+	for _, r := range relevance {
+		lo, hi = math.Min(lo, r), math.Max(hi, r)
+	}
+
+	values := make(map[int64]map[string]float64, len(journals))
+	for _, signal := range signals {
+		if values[signal.PMID] == nil {
+			values[signal.PMID] = make(map[string]float64)
+		}
+		values[signal.PMID][signal.Name] = signal.Value
+	}
+
+	for i := range journals {
+		normalized := 1.0
+		if hi > lo {
+			normalized = (relevance[i] - lo) / (hi - lo)
+		}
+		components := []domain.ScoreComponent{{
+			Name: domain.RelevanceComponent, Raw: relevance[i], Value: normalized, Weight: 1,
+		}}
+
+		if settings.RecencyWeight > 0 {
+			recency, age := settings.Recency(journals[i].PublicationDate, now)
+			components = append(components, domain.ScoreComponent{
+				Name: domain.RecencyComponent, Raw: age, Value: recency, Weight: settings.RecencyWeight,
+			})
+		}
+		for _, boost := range settings.Signals {
+			if boost.Weight <= 0 {
+				continue
+			}
+			raw := max(values[journals[i].PMID][boost.Signal], 0)
+			components = append(components, domain.ScoreComponent{
+				Name: boost.Signal, Raw: raw, Value: raw / (raw + boost.Pivot), Weight: boost.Weight,
+			})
+		}
+
+		score := 0.0
+		for _, c := range components {
+			score += c.Weight * c.Value
+		}
+		journals[i].Distance = score
+		journals[i].ScoreComponents = components
+	}
+}
+
+// boostedSignals returns the names of the signals settings boosts by.
+func boostedSignals(settings domain.RankingSettings) []string {
+	var names []string
+	for _, boost := range settings.Signals {
+		if boost.Weight > 0 {
+			names = append(names, boost.Signal)
+		}
+	}
+	return names
+}